@@ -0,0 +1,95 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// AliasSampler picks indexes at random in proportion to their weights, using Vose's alias method.
+// Building the sampler takes O(n) time, after which each pick takes O(1) time however the weights
+// are distributed.
+type AliasSampler struct {
+	prob  []float64
+	alias []int
+}
+
+// NewAliasSampler creates a sampler for weights. Weights must not be negative, and at least one
+// must be positive. An index with a weight of zero is never picked.
+func NewAliasSampler(weights []float64) (*AliasSampler, error) {
+	total := 0.0
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, errors.New("weights must not be negative")
+		}
+
+		total += weight
+	}
+
+	if total <= 0 {
+		return nil, errors.New("weights must include a positive weight")
+	}
+
+	n := len(weights)
+	a := &AliasSampler{prob: make([]float64, n), alias: make([]int, n)}
+
+	scaled := make([]float64, n)
+	var small, large []int
+
+	for i, weight := range weights {
+		scaled[i] = weight * float64(n) / total
+
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+
+		a.prob[s] = scaled[s]
+		a.alias[s] = l
+
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+
+	// Whatever is left over is only short of a full column due to rounding errors, so is always
+	// picked when chosen, unless it should never be picked at all.
+	for _, i := range append(small, large...) {
+		if weights[i] > 0 {
+			a.prob[i] = 1
+		}
+
+		a.alias[i] = i
+	}
+
+	return a, nil
+}
+
+// Len returns the number of weights the sampler picks from.
+func (a *AliasSampler) Len() int {
+	return len(a.prob)
+}
+
+// Sample picks an index using rng, or the default source if rng is nil.
+func (a *AliasSampler) Sample(rng *rand.Rand) int {
+	for {
+		i := intn(rng, len(a.prob))
+
+		if randFloat(rng) < a.prob[i] {
+			return i
+		}
+
+		// A leftover index with a weight of zero has no alias, so pick again.
+		if a.alias[i] != i {
+			return a.alias[i]
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package forensicfilescorpus
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNewAliasSamplerInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+	}{
+		{"empty", nil},
+		{"all zero", []float64{0, 0, 0}},
+		{"negative", []float64{1, -1, 2}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewAliasSampler(test.weights); err == nil {
+			t.Errorf("%s: NewAliasSampler(%v) error = nil, want an error", test.name, test.weights)
+		}
+	}
+}
+
+func TestAliasSamplerDistribution(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights []float64
+	}{
+		{"single", []float64{3}},
+		{"uniform", []float64{1, 1, 1, 1}},
+		{"skewed", []float64{1, 2, 3, 0, 4}},
+		{"zeros around one weight", []float64{0, 0, 5, 0}},
+		{"tiny and huge", []float64{1e-6, 1, 1e6}},
+		{"rounding leftovers", []float64{0.1, 0.2, 0.3, 0.1, 0.2, 0.1}},
+	}
+
+	const samples = 200000
+
+	for _, test := range tests {
+		sampler, err := NewAliasSampler(test.weights)
+		if err != nil {
+			t.Fatalf("%s: NewAliasSampler() error = %v", test.name, err)
+		}
+
+		if sampler.Len() != len(test.weights) {
+			t.Errorf("%s: Len() = %d, want %d", test.name, sampler.Len(), len(test.weights))
+		}
+
+		total := 0.0
+		for _, weight := range test.weights {
+			total += weight
+		}
+
+		rng := rand.New(rand.NewSource(1))
+		counts := make([]int, len(test.weights))
+		for i := 0; i < samples; i++ {
+			counts[sampler.Sample(rng)]++
+		}
+
+		for i, weight := range test.weights {
+			if weight == 0 && counts[i] > 0 {
+				t.Errorf("%s: Sample() picked index %d with a weight of zero %d times", test.name, i, counts[i])
+			}
+
+			want := weight / total
+			got := float64(counts[i]) / samples
+			if math.Abs(got-want) > 0.01 {
+				t.Errorf("%s: Sample() picked index %d %.4f of the time, want %.4f", test.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestAliasSamplerDefaultSource(t *testing.T) {
+	sampler, err := NewAliasSampler([]float64{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := sampler.Sample(nil); got != 1 {
+			t.Fatalf("Sample(nil) = %d, want 1", got)
+		}
+	}
+}
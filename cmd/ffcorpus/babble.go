@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func babble() {
+	fs := flag.NewFlagSet("babble", flag.ExitOnError)
+	unit := unitFlag(fs)
+	seed := seedFlag(fs)
+	order := fs.Int("order", 2, "number of words each word is chosen from")
+	overlap := fs.Int("overlap", 8, "most consecutive words a sentence may share with the corpus")
+	model := fs.String("model", "", "load a saved markov chain rather than training one")
+	save := fs.String("save", "", "save the trained markov chain to this file")
+	n := fs.Int("n", 1, "number of sentences to generate")
+	path, args := corpusArgs(parseArgs(fs, os.Args[2:]))
+
+	if len(args) > 2 {
+		fmt.Println("USAGE: ffcorpus babble [--unit runes] [--seed n] [--order 2] [--overlap 8] [--model chain.json] [--save chain.json] [-n 1] [sentences.txt|corpus.ffc|url] [min] [max]")
+		os.Exit(1)
+	}
+
+	options, err := corpusOptions(fs, *unit, *seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	min := -1
+	max := -1
+
+	if len(args) > 0 {
+		min, err = strconv.Atoi(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(args) > 1 {
+		max, err = strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var chain *forensicfilescorpus.Markov
+
+	if *model != "" {
+		chain, err = forensicfilescorpus.ReadMarkovFile(*model, options...)
+	} else {
+		chain, err = trainMarkov(path, *order, *overlap, options)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *save != "" {
+		if err := chain.WriteFile(*save); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for i := 0; i < *n; i++ {
+		sentence, err := chain.Generate(min, max)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(sentence)
+	}
+
+	os.Exit(0)
+}
+
+func trainMarkov(path string, order, overlap int, options []forensicfilescorpus.Option) (*forensicfilescorpus.Markov, error) {
+	corpus, err := forensicfilescorpus.LoadCorpus(path, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return forensicfilescorpus.NewMarkov(corpus, order, overlap)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func compile() {
+	fs := flag.NewFlagSet("compile", flag.ExitOnError)
+	unit := unitFlag(fs)
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) != 2 {
+		fmt.Println("USAGE: ffcorpus compile [--unit runes] sentences.txt corpus.ffc")
+		os.Exit(1)
+	}
+
+	options, err := unitOptions(fs, *unit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := forensicfilescorpus.CompileFile(args[0], args[1], options...); err != nil {
+		log.Fatal(err)
+	}
+
+	os.Exit(0)
+}
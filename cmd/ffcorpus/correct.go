@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func correct() {
+	fs := flag.NewFlagSet("correct", flag.ExitOnError)
+	dictionary := fs.String("dictionary", "", "file of real words, one per line, that are never corrected")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) != 2 {
+		fmt.Println("USAGE: ffcorpus correct [--dictionary words.txt] sentences.txt corrected.txt")
+		os.Exit(1)
+	}
+
+	stripped, err := forensicfilescorpus.ReadSentencesFile(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var sentences []string
+	for _, sentence := range stripped {
+		sentences = append(sentences, sentence.Text)
+	}
+
+	corrector := forensicfilescorpus.NewCorrector(sentences)
+
+	if *dictionary != "" {
+		corrector.Dictionary, err = forensicfilescorpus.ReadDictionaryFile(*dictionary)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	corrected, review := corrector.CorrectAll(sentences)
+
+	output := args[1]
+	dest, err := forensicfilescorpus.CreateFile(output, forensicfilescorpus.CompressionOf(output))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, sentence := range corrected {
+		stripped[i].Text = sentence
+	}
+
+	if forensicfilescorpus.IsJSONLines(output) {
+		err = forensicfilescorpus.WriteJSONLines(dest, stripped)
+	} else {
+		for _, sentence := range corrected {
+			fmt.Fprintln(dest, sentence)
+		}
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := dest.Close(); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, correction := range review {
+		fmt.Printf("%d:%d\t%s -> %s (%.2f)\t%s\n", correction.Sentence+1, correction.Offset,
+			correction.Original, correction.Suggested, correction.Confidence, corrected[correction.Sentence])
+	}
+
+	os.Exit(0)
+}
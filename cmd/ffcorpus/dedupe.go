@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func dedupe() {
+	if len(os.Args) < 3 {
+		fmt.Println("USAGE: ffcorpus dedupe-sources *.srt")
+		os.Exit(1)
+	}
+
+	paths := os.Args[2:]
+	groups := forensicfilescorpus.GroupSources(paths)
+
+	for i, group := range groups {
+		fmt.Printf("group %d\n", i+1)
+
+		for j, source := range group {
+			marker := " "
+			if j == 0 {
+				marker = "*"
+			}
+
+			q := source.Quality
+			fmt.Printf("  %s %s (score %.1f, %d sentences, %d cues, %d ignored, %d caps, %d markup)\n",
+				marker, source.Path, q.Score, q.Sentences, q.Cues, q.Ignored, q.UpperCase, q.Markup)
+		}
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+// parseArgs parses the flags within args, allowing them to appear before, between, or after
+// positional arguments. The positional arguments are returned in order. Negative numbers are
+// treated as positional arguments rather than flags, so "-1" can still be passed as a min or max,
+// unless they are the value of a flag, as in "--seed -5".
+func parseArgs(fs *flag.FlagSet, args []string) (positional []string) {
+	for len(args) > 0 {
+		if !isFlag(args[0]) {
+			positional = append(positional, args[0])
+			args = args[1:]
+			continue
+		}
+
+		if args[0] == "--" {
+			return append(positional, args[1:]...)
+		}
+
+		end := 1
+		if takesValue(fs, args[0]) && len(args) > 1 {
+			end = 2
+		}
+
+		fs.Parse(args[:end])
+		args = args[end:]
+	}
+
+	return positional
+}
+
+// takesValue reports whether the flag arg is followed by its value, rather than being a boolean
+// flag or giving its value after "=". Unknown flags are left for the flag set to report.
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if strings.Contains(name, "=") {
+		return false
+	}
+
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !b.IsBoolFlag()
+}
+
+func isFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && arg != "-" && !isNegativeNumber(arg)
+}
+
+func isNegativeNumber(arg string) bool {
+	_, err := strconv.Atoi(arg)
+	return err == nil && strings.HasPrefix(arg, "-")
+}
+
+// corpusArgs splits the path to the corpus from the remaining positional arguments. The path can
+// be left out, in which case the first argument is a number and the embedded default corpus is
+// used, as given by an empty path.
+func corpusArgs(args []string) (path string, rest []string) {
+	if len(args) == 0 {
+		return "", args
+	}
+
+	if _, err := strconv.Atoi(args[0]); err == nil {
+		return "", args
+	}
+
+	return args[0], args[1:]
+}
+
+// unitFlag adds a --unit flag to fs for choosing how sentence lengths are measured.
+func unitFlag(fs *flag.FlagSet) *string {
+	return fs.String("unit", forensicfilescorpus.DefaultUnit.Name, "unit for min and max: bytes, runes, graphemes or words")
+}
+
+// unitOptions returns the option for the unit given by the --unit flag. No option is returned when
+// the flag was not given, so a compiled corpus keeps the unit it was compiled with.
+func unitOptions(fs *flag.FlagSet, name string) ([]forensicfilescorpus.Option, error) {
+	if !flagGiven(fs, "unit") {
+		return nil, nil
+	}
+
+	unit, err := forensicfilescorpus.UnitByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []forensicfilescorpus.Option{forensicfilescorpus.WithUnit(unit)}, nil
+}
+
+// seedFlag adds a --seed flag to fs for choosing the seed used for randomisation.
+func seedFlag(fs *flag.FlagSet) *int64 {
+	return fs.Int64("seed", 0, "seed for randomisation, a random seed is used when not given")
+}
+
+// corpusOptions returns the options for loading a corpus from the --unit and --seed flags. The
+// unit is only given when the flag was set, so a compiled corpus keeps the unit it was compiled
+// with. A source of randomness is always given, see `seededSource`.
+func corpusOptions(fs *flag.FlagSet, unit string, seed int64) ([]forensicfilescorpus.Option, error) {
+	options, err := unitOptions(fs, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(options, seededSource(fs, seed)), nil
+}
+
+// flagGiven reports whether the flag with the given name was set on the command line.
+func flagGiven(fs *flag.FlagSet, name string) bool {
+	given := false
+	fs.Visit(func(f *flag.Flag) {
+		given = given || f.Name == name
+	})
+
+	return given
+}
+
+// seededSource returns a source of randomness seeded with the --seed flag, or the current time if
+// the flag was not given. The seed is printed to stderr so that any output can be reproduced.
+func seededSource(fs *flag.FlagSet, seed int64) forensicfilescorpus.Option {
+	if !flagGiven(fs, "seed") {
+		seed = time.Now().UnixNano()
+	}
+
+	fmt.Fprintf(os.Stderr, "seed: %d\n", seed)
+	return forensicfilescorpus.WithSource(rand.NewSource(seed))
+}
+
+// stringsFlag is a flag that can be given more than once, collecting every value.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// filterFlags adds --contains, --exclude, --ends and --match flags to fs for restricting which
+// sentences are picked. The returned function gives the options for the flags once fs is parsed.
+func filterFlags(fs *flag.FlagSet) func() ([]forensicfilescorpus.Option, error) {
+	var contains, exclude, ends stringsFlag
+	fs.Var(&contains, "contains", "only pick sentences containing this word or phrase, can be repeated")
+	fs.Var(&exclude, "exclude", "never pick sentences containing this word or phrase, can be repeated")
+	fs.Var(&ends, "ends", "only pick sentences ending with this punctuation, such as '?', can be repeated")
+	match := fs.String("match", "", "only pick sentences matching this regular expression")
+
+	return func() ([]forensicfilescorpus.Option, error) {
+		var filters []forensicfilescorpus.Filter
+
+		if len(contains) > 0 {
+			filters = append(filters, forensicfilescorpus.Contains(contains...))
+		}
+
+		if len(exclude) > 0 {
+			filters = append(filters, forensicfilescorpus.Excludes(exclude...))
+		}
+
+		if len(ends) > 0 {
+			filters = append(filters, forensicfilescorpus.EndsWith(ends...))
+		}
+
+		if *match != "" {
+			re, err := regexp.Compile(*match)
+			if err != nil {
+				return nil, err
+			}
+
+			filters = append(filters, forensicfilescorpus.Matches(re))
+		}
+
+		if len(filters) == 0 {
+			return nil, nil
+		}
+
+		return []forensicfilescorpus.Option{forensicfilescorpus.WithFilter(filters...)}, nil
+	}
+}
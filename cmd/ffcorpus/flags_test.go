@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args       string
+		seed       int64
+		unit       string
+		json       bool
+		positional string
+	}{
+		{"sentences.txt 10 20", 0, "runes", false, "sentences.txt 10 20"},
+		{"--seed 5 sentences.txt", 5, "runes", false, "sentences.txt"},
+		{"--seed -5", -5, "runes", false, ""},
+		{"--seed=-5 -1 20", -5, "runes", false, "-1 20"},
+		{"sentences.txt --seed -5 -1 20", -5, "runes", false, "sentences.txt -1 20"},
+		{"--unit words -1 50", 0, "words", false, "-1 50"},
+		{"--json -1 50", 0, "runes", true, "-1 50"},
+		{"-1 --json 50 --unit bytes", 0, "bytes", true, "-1 50"},
+		{"--seed 3 -- --unit", 3, "runes", false, "--unit"},
+	}
+
+	for _, test := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		seed := seedFlag(fs)
+		unit := unitFlag(fs)
+		json := fs.Bool("json", false, "")
+
+		positional := parseArgs(fs, strings.Fields(test.args))
+
+		if *seed != test.seed || *unit != test.unit || *json != test.json {
+			t.Errorf("parseArgs(%q) flags = %d %s %t, want %d %s %t", test.args, *seed, *unit, *json, test.seed, test.unit, test.json)
+		}
+
+		if got := strings.Join(positional, " "); got != test.positional {
+			t.Errorf("parseArgs(%q) positional = %q, want %q", test.args, got, test.positional)
+		}
+	}
+}
+
+func TestUnitOptions(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	unit := unitFlag(fs)
+	parseArgs(fs, nil)
+
+	if options, err := unitOptions(fs, *unit); err != nil || len(options) != 0 {
+		t.Errorf("unitOptions() without --unit = %d options, %v, want none", len(options), err)
+	}
+
+	parseArgs(fs, []string{"--unit", "words"})
+
+	if options, err := unitOptions(fs, *unit); err != nil || len(options) != 1 {
+		t.Errorf("unitOptions() with --unit = %d options, %v, want one", len(options), err)
+	}
+
+	parseArgs(fs, []string{"--unit", "furlongs"})
+
+	if _, err := unitOptions(fs, *unit); err == nil {
+		t.Error("unitOptions() with an unknown unit returned no error")
+	}
+}
+
+func TestFilterFlags(t *testing.T) {
+	tests := []struct {
+		args    string
+		options int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"--contains DNA", 1, false},
+		{"--contains DNA --contains blood --exclude van --ends ? --match ^The", 1, false},
+		{"--match [", 0, true},
+	}
+
+	for _, test := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		filters := filterFlags(fs)
+		parseArgs(fs, strings.Fields(test.args))
+
+		options, err := filters()
+		if (err != nil) != test.wantErr {
+			t.Errorf("filterFlags(%q) error = %v, want error %v", test.args, err, test.wantErr)
+			continue
+		}
+
+		if len(options) != test.options {
+			t.Errorf("filterFlags(%q) = %d options, want %d", test.args, len(options), test.options)
+		}
+	}
+}
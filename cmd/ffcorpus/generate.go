@@ -1,48 +1,77 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
 	"strconv"
-	"time"
 
 	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
 )
 
 func generate() {
-	if len(os.Args) < 3 {
-		fmt.Println("USAGE: ffcorpus generate sentences.txt [min] [max]")
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	unit := unitFlag(fs)
+	seed := seedFlag(fs)
+	filters := filterFlags(fs)
+	coherent := fs.Bool("coherent", false, "continue with the sentences that follow within the same episode")
+	exact := fs.Bool("exact", false, "search for a set of sentences that fits within min and max")
+	path, args := corpusArgs(parseArgs(fs, os.Args[2:]))
+
+	if len(args) > 2 {
+		fmt.Println("USAGE: ffcorpus generate [--unit runes] [--seed n] [--coherent] [--exact] [--contains word] [--exclude word] [--ends ?] [--match regexp] [sentences.txt|corpus.ffc|url] [min] [max]")
 		os.Exit(1)
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	var err error
+	options, err := corpusOptions(fs, *unit, *seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filterOptions, err := filters()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options = append(options, filterOptions...)
+
 	min := 140
 	max := 280
 
-	if len(os.Args) == 4 {
-		min, err = strconv.Atoi(os.Args[3])
+	if len(args) == 1 {
+		min, err = strconv.Atoi(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 
-	if len(os.Args) == 5 {
-		min, err = strconv.Atoi(os.Args[3])
+	if len(args) == 2 {
+		min, err = strconv.Atoi(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		max, err = strconv.Atoi(os.Args[4])
+		max, err = strconv.Atoi(args[1])
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 
-	path := os.Args[2]
-	paragraph, err := forensicfilescorpus.GenerateFromFile(path, min, max)
+	generateFromFile := forensicfilescorpus.GenerateFromFile
+	if *coherent && *exact {
+		log.Fatal("--coherent and --exact cannot be used together")
+	}
+
+	if *coherent {
+		generateFromFile = forensicfilescorpus.GenerateCoherentFromFile
+	}
+
+	if *exact {
+		generateFromFile = forensicfilescorpus.GenerateExactFromFile
+	}
+
+	paragraph, err := generateFromFile(path, min, max, options...)
 	if err != nil {
 		log.Fatal(err)
 	}
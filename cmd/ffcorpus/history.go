@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func history() {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	var search stringsFlag
+	fs.Var(&search, "search", "only list posts containing this word or phrase, can be repeated")
+	since := fs.Duration("since", 0, "only list posts from within this long ago, such as 720h")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) != 1 {
+		fmt.Println("USAGE: ffcorpus history [--search word] [--since 720h] history.jsonl|history.db|s3://bucket/key")
+		os.Exit(1)
+	}
+
+	store, err := forensicfilescorpus.HistoryStoreFor(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entries, err := store.Entries()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	contains := forensicfilescorpus.Contains(search...)
+
+	for _, entry := range entries {
+		if *since > 0 && entry.Posted.Before(time.Now().Add(-*since)) {
+			continue
+		}
+
+		if !contains(forensicfilescorpus.Sentence{Text: entry.Text}) {
+			continue
+		}
+
+		fmt.Printf("%s\t%s\t%s\n", entry.Posted.Format(time.RFC3339), entry.URL, entry.Text)
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func kwic() {
+	fs := flag.NewFlagSet("kwic", flag.ExitOnError)
+	width := fs.Int("width", 40, "number of characters of context either side of the term")
+	order := fs.String("sort", "corpus", "order of the lines: corpus, left or right")
+	group := fs.Bool("group", true, "group the lines by episode when the corpus has episodes")
+	tsv := fs.Bool("tsv", false, "print the lines as tab separated values")
+	args := parseArgs(fs, os.Args[2:])
+
+	path := ""
+	if len(args) == 2 {
+		path, args = args[0], args[1:]
+	}
+
+	if len(args) != 1 {
+		fmt.Println("USAGE: ffcorpus kwic [--width 40] [--sort corpus|left|right] [--group=false] [--tsv] [sentences.txt|corpus.ffc|url] term")
+		os.Exit(1)
+	}
+
+	orders := map[string]forensicfilescorpus.KWICOrder{
+		"corpus": forensicfilescorpus.KWICCorpusOrder,
+		"left":   forensicfilescorpus.KWICLeftOrder,
+		"right":  forensicfilescorpus.KWICRightOrder,
+	}
+
+	kwicOrder, ok := orders[*order]
+	if !ok {
+		log.Fatal("unknown sort order, must be corpus, left or right")
+	}
+
+	lines, err := forensicfilescorpus.KWICFromFile(path, args[0], *width)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	forensicfilescorpus.SortKWIC(lines, kwicOrder)
+
+	groups := []forensicfilescorpus.KWICGroup{{Lines: lines}}
+	if *group {
+		groups = forensicfilescorpus.GroupKWIC(lines)
+	}
+
+	if *tsv {
+		var ordered []forensicfilescorpus.KWICLine
+		for _, group := range groups {
+			ordered = append(ordered, group.Lines...)
+		}
+
+		if err := forensicfilescorpus.WriteKWIC(os.Stdout, ordered); err != nil {
+			log.Fatal(err)
+		}
+
+		os.Exit(0)
+	}
+
+	for _, group := range groups {
+		if group.Episode != "" {
+			fmt.Printf("%s\n", group.Episode)
+		}
+
+		for _, line := range group.Lines {
+			fmt.Printf("%*s  %s  %s\n", *width, line.Left, line.Match, line.Right)
+		}
+	}
+
+	os.Exit(0)
+}
@@ -3,9 +3,13 @@ package main
 import (
 	"fmt"
 	"os"
+
+	"github.com/karlbright/forensic-files-corpus/defaultcorpus"
 )
 
 func main() {
+	defaultcorpus.Register()
+
 	if len(os.Args) < 2 {
 		usage()
 	}
@@ -17,6 +21,24 @@ func main() {
 		pick()
 	case "generate":
 		generate()
+	case "dedupe-sources":
+		dedupe()
+	case "correct":
+		correct()
+	case "compile":
+		compile()
+	case "stats":
+		stats()
+	case "search":
+		search()
+	case "kwic":
+		kwic()
+	case "history":
+		history()
+	case "score":
+		score()
+	case "babble":
+		babble()
 	default:
 		usage()
 	}
@@ -25,9 +47,18 @@ func main() {
 }
 
 func usage() {
-	fmt.Println("USAGE: ffcorpus generate sentences.txt [min] [max]")
-	fmt.Println("USAGE: ffcorpus pick sentences.txt [min] [max]")
-	fmt.Println("USAGE: ffcorpus strip *.srt sentences.txt")
+	fmt.Println("USAGE: ffcorpus generate [--unit runes] [--seed n] [--coherent] [--exact] [--contains word] [--exclude word] [--ends ?] [--match regexp] [sentences.txt|corpus.ffc|url] [min] [max]")
+	fmt.Println("USAGE: ffcorpus pick [--unit runes] [--seed n] [--contains word] [--exclude word] [--ends ?] [--match regexp] [--stream] [-n 1] [--diverse episode|speaker] [--json] [sentences.txt|corpus.ffc|url] [min] [max]")
+	fmt.Println("USAGE: ffcorpus strip [--compress gzip|zstd] *.srt sentences.txt")
+	fmt.Println("USAGE: ffcorpus dedupe-sources *.srt")
+	fmt.Println("USAGE: ffcorpus correct [--dictionary words.txt] sentences.txt corrected.txt")
+	fmt.Println("USAGE: ffcorpus compile [--unit runes] sentences.txt corpus.ffc")
+	fmt.Println("USAGE: ffcorpus stats [--unit runes] [--top 20] [--json] [sentences.txt|corpus.ffc|url]")
+	fmt.Println(`USAGE: ffcorpus search [--limit 20] [--stem] [sentences.txt|corpus.ffc|url] "query"`)
+	fmt.Println("USAGE: ffcorpus kwic [--width 40] [--sort corpus|left|right] [--group=false] [--tsv] [sentences.txt|corpus.ffc|url] term")
+	fmt.Println("USAGE: ffcorpus history [--search word] [--since 720h] history.jsonl|history.db|s3://bucket/key")
+	fmt.Println("USAGE: ffcorpus score [--ratings ratings.tsv] [--sort] [sentences.txt|corpus.ffc|url]")
+	fmt.Println("USAGE: ffcorpus babble [--unit runes] [--seed n] [--order 2] [--overlap 8] [--model chain.json] [--save chain.json] [-n 1] [sentences.txt|corpus.ffc|url] [min] [max]")
 	fmt.Println("USAGE: ffcorpus tweet sentences.txt")
 	os.Exit(0)
 }
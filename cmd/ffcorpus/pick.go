@@ -1,48 +1,93 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
 	"strconv"
-	"time"
 
 	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
 )
 
 func pick() {
-	if len(os.Args) < 3 {
-		fmt.Println("USAGE: ffcorpus pick sentences.txt [min] [max]")
+	fs := flag.NewFlagSet("pick", flag.ExitOnError)
+	unit := unitFlag(fs)
+	seed := seedFlag(fs)
+	filters := filterFlags(fs)
+	stream := fs.Bool("stream", false, "pick in a single pass without loading the whole file")
+	n := fs.Int("n", 1, "number of distinct sentences to pick")
+	diverse := fs.String("diverse", "", "spread the sentences across each episode or speaker")
+	asJSON := fs.Bool("json", false, "print the sentences as JSON Lines")
+	path, args := corpusArgs(parseArgs(fs, os.Args[2:]))
+
+	if len(args) > 2 {
+		fmt.Println("USAGE: ffcorpus pick [--unit runes] [--seed n] [--contains word] [--exclude word] [--ends ?] [--match regexp] [--stream] [-n 1] [--diverse episode|speaker] [--json] [sentences.txt|corpus.ffc|url] [min] [max]")
 		os.Exit(1)
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	var err error
+	options, err := corpusOptions(fs, *unit, *seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filterOptions, err := filters()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options = append(options, filterOptions...)
+
 	min := -1
 	max := -1
 
-	if len(os.Args) == 4 {
-		min, err = strconv.Atoi(os.Args[3])
+	if len(args) == 1 {
+		min, err = strconv.Atoi(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 
-	if len(os.Args) == 5 {
-		min, err = strconv.Atoi(os.Args[3])
+	if len(args) == 2 {
+		min, err = strconv.Atoi(args[0])
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		max, err = strconv.Atoi(os.Args[4])
+		max, err = strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *n != 1 || *diverse != "" || *asJSON {
+		sentences, err := pickN(path, *n, *diverse, *stream, min, max, options)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if *asJSON {
+			err = forensicfilescorpus.WriteJSONLines(os.Stdout, sentences)
+		} else {
+			for _, sentence := range sentences {
+				fmt.Println(sentence.Text)
+			}
+		}
+
 		if err != nil {
 			log.Fatal(err)
 		}
+
+		os.Exit(0)
+	}
+
+	pickFromFile := forensicfilescorpus.PickFromFile
+	if *stream {
+		pickFromFile = forensicfilescorpus.StreamPickFromFile
 	}
 
-	path := os.Args[2]
-	sentence, err := forensicfilescorpus.PickFromFile(path, min, max)
+	sentence, err := pickFromFile(path, min, max, options...)
 
 	if err != nil {
 		log.Fatal(err)
@@ -51,3 +96,43 @@ func pick() {
 	fmt.Println(sentence)
 	os.Exit(0)
 }
+
+// pickN picks n distinct sentences, either by loading the corpus or by streaming it. Streaming does
+// not keep the metadata of sentences, so cannot spread them across episodes or speakers.
+func pickN(path string, n int, diverse string, stream bool, min, max int, options []forensicfilescorpus.Option) ([]forensicfilescorpus.Sentence, error) {
+	diversities := map[string]forensicfilescorpus.Diversity{
+		"":        nil,
+		"episode": forensicfilescorpus.ByEpisode,
+		"speaker": forensicfilescorpus.BySpeaker,
+	}
+
+	diversity, ok := diversities[diverse]
+	if !ok {
+		return nil, errors.New("unknown diversity, must be episode or speaker")
+	}
+
+	if stream {
+		if diversity != nil {
+			return nil, errors.New("unable to spread sentences across episodes or speakers when streaming")
+		}
+
+		texts, err := forensicfilescorpus.SampleFile(path, n, forensicfilescorpus.Between(min, max), options...)
+		if err != nil {
+			return nil, err
+		}
+
+		sentences := make([]forensicfilescorpus.Sentence, len(texts))
+		for i, text := range texts {
+			sentences[i] = forensicfilescorpus.Sentence{Text: text}
+		}
+
+		return sentences, nil
+	}
+
+	corpus, err := forensicfilescorpus.LoadCorpus(path, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return corpus.PickNDiverse(n, diversity, forensicfilescorpus.Between(min, max))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func score() {
+	fs := flag.NewFlagSet("score", flag.ExitOnError)
+	ratings := fs.String("ratings", "", "manual ratings to use in place of the heuristic scores")
+	sorted := fs.Bool("sort", false, "list the best sentences first")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) > 1 {
+		fmt.Println("USAGE: ffcorpus score [--ratings ratings.tsv] [--sort] [sentences.txt|corpus.ffc|url]")
+		os.Exit(1)
+	}
+
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	corpus, err := forensicfilescorpus.LoadCorpus(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	scorer, err := forensicfilescorpus.NewScorer(corpus, *ratings)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sentences := corpus.Sentences()
+	scores := make([]float64, len(sentences))
+	order := make([]int, len(sentences))
+
+	for i, sentence := range sentences {
+		scores[i] = scorer.Score(sentence)
+		order[i] = i
+	}
+
+	if *sorted {
+		sort.SliceStable(order, func(i, j int) bool {
+			return scores[order[i]] > scores[order[j]]
+		})
+	}
+
+	// The output is in the same format as manual ratings, so it can be edited and used as ratings.
+	for _, i := range order {
+		fmt.Printf("%.3f\t%s\n", scores[i], sentences[i].Text)
+	}
+
+	os.Exit(0)
+}
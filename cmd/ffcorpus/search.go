@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func search() {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "number of results to show, or 0 for every result")
+	stem := fs.Bool("stem", false, "match other forms of each word, such as fiber for fibers")
+	args := parseArgs(fs, os.Args[2:])
+
+	path := ""
+	if len(args) == 2 {
+		path, args = args[0], args[1:]
+	}
+
+	if len(args) != 1 {
+		fmt.Println(`USAGE: ffcorpus search [--limit 20] [--stem] [sentences.txt|corpus.ffc|url] "query"`)
+		os.Exit(1)
+	}
+
+	var options []forensicfilescorpus.Option
+	if *stem {
+		options = append(options, forensicfilescorpus.WithStemmer(forensicfilescorpus.Stem))
+	}
+
+	corpus, err := forensicfilescorpus.LoadCorpus(path, options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	results, err := corpus.Search(args[0], *limit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, result := range results {
+		text := forensicfilescorpus.Highlight(result.Sentence.Text, result.Matches, "[", "]")
+		fmt.Printf("%.2f\t%s\n", result.Score, text)
+	}
+
+	os.Exit(0)
+}
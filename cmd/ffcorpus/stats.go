@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func stats() {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	unit := unitFlag(fs)
+	top := fs.Int("top", 20, "number of words and bigrams to report")
+	asJSON := fs.Bool("json", false, "print the statistics as JSON")
+	path, args := corpusArgs(parseArgs(fs, os.Args[2:]))
+
+	if len(args) > 0 {
+		fmt.Println("USAGE: ffcorpus stats [--unit runes] [--top 20] [--json] [sentences.txt|corpus.ffc|url]")
+		os.Exit(1)
+	}
+
+	options, err := unitOptions(fs, *unit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stats, err := forensicfilescorpus.StatsFromFile(path, *top, options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON {
+		out, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(string(out))
+		os.Exit(0)
+	}
+
+	printStats(stats, *top)
+	os.Exit(0)
+}
+
+// histogramWidth is the width of the longest bar within the length histogram.
+const histogramWidth = 40
+
+func printStats(stats forensicfilescorpus.Stats, top int) {
+	length := stats.Length
+
+	fmt.Printf("sentences:      %d\n", stats.Sentences)
+	fmt.Printf("vocabulary:     %d\n", stats.Vocabulary)
+	fmt.Printf("words/sentence: %.1f\n", stats.AverageWords)
+
+	fmt.Printf("\nlength (%s): min %d, max %d, mean %.1f\n", stats.Unit, length.Min, length.Max, length.Mean)
+	for _, percentile := range length.Percentiles {
+		fmt.Printf("  p%-3d %d\n", percentile.P, percentile.Length)
+	}
+
+	most := 0
+	for _, bucket := range length.Histogram {
+		if bucket.Count > most {
+			most = bucket.Count
+		}
+	}
+
+	fmt.Println()
+	for _, bucket := range length.Histogram {
+		bar := 0
+		if most > 0 {
+			bar = bucket.Count * histogramWidth / most
+		}
+
+		fmt.Printf("  %4d-%-4d %-*s %d\n", bucket.Min, bucket.Max, histogramWidth, strings.Repeat("#", bar), bucket.Count)
+	}
+
+	var punctuation []string
+	for ending := range stats.Punctuation {
+		punctuation = append(punctuation, ending)
+	}
+	sort.Strings(punctuation)
+
+	fmt.Println("\nend punctuation:")
+	for _, ending := range punctuation {
+		label := ending
+		if label == "" {
+			label = "none"
+		}
+
+		fmt.Printf("  %-5s %d\n", label, stats.Punctuation[ending])
+	}
+
+	printCounts("top words", stats.TopWords, top)
+	printCounts("top bigrams", stats.TopBigrams, top)
+	printCounts("episodes", stats.Episodes, top)
+	printCounts("speakers", stats.Speakers, top)
+}
+
+// printCounts prints up to top counts under a heading, noting how many were left out.
+func printCounts(heading string, counts []forensicfilescorpus.Count, top int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s:\n", heading)
+	for i, count := range counts {
+		if i == top {
+			fmt.Printf("  ... and %d more\n", len(counts)-top)
+			break
+		}
+
+		fmt.Printf("  %-24s %d\n", count.Value, count.Count)
+	}
+}
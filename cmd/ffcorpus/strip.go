@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -9,15 +10,27 @@ import (
 )
 
 func strip() {
-	if len(os.Args) < 4 {
-		fmt.Println("USAGE: ffcorpus strip *.srt sentences.txt")
+	fs := flag.NewFlagSet("strip", flag.ExitOnError)
+	compress := fs.String("compress", "", "compress the output: gzip, zstd or none, taken from the extension when not given")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("USAGE: ffcorpus strip [--compress gzip|zstd] *.srt sentences.txt")
 		os.Exit(1)
 	}
 
-	paths := os.Args[2 : len(os.Args)-2]
-	output := os.Args[len(os.Args)-1]
+	paths := args[:len(args)-1]
+	output := args[len(args)-1]
+
+	compression := forensicfilescorpus.CompressionOf(output)
+	if *compress != "" {
+		var err error
+		if compression, err = forensicfilescorpus.ParseCompression(*compress); err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	if err := forensicfilescorpus.StripAllToFile(paths, output); err != nil {
+	if err := forensicfilescorpus.StripAllToCompressedFile(paths, output, compression); err != nil {
 		log.Fatal(err)
 	}
 
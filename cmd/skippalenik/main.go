@@ -10,6 +10,7 @@ import (
 	"github.com/dghubble/go-twitter/twitter"
 	"github.com/dghubble/oauth1"
 	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+	"github.com/karlbright/forensic-files-corpus/defaultcorpus"
 )
 
 func Handler() (string, error) {
@@ -17,22 +18,90 @@ func Handler() (string, error) {
 	token := oauth1.NewToken(os.Getenv("ACCESS_TOKEN"), os.Getenv("ACCESS_SECRET"))
 	client := twitter.NewClient(config.Client(oauth1.NoContext, token))
 
-	rand.Seed(time.Now().UnixNano())
+	seed := time.Now().UnixNano()
+	fmt.Printf("seed: %d\n", seed)
 
-	pick, err := forensicfilescorpus.PickFromFile("sentences.txt", 0, 280)
+	// When no corpus is given the embedded default corpus is used. The corpus can be a path, or an
+	// https:// or s3:// URL which is cached within /tmp between invocations, see
+	// `forensicfilescorpus.SourceFor`.
+	corpus, err := forensicfilescorpus.LoadCorpus(os.Getenv("SKIPPALENIK_CORPUS"),
+		forensicfilescorpus.WithUnit(forensicfilescorpus.Twitter.Unit),
+		forensicfilescorpus.WithSource(rand.NewSource(seed)))
 	if err != nil {
 		return "", err
 	}
 
-	tweet, _, err := client.Statuses.Update(pick, nil)
+	// SKIPPALENIK_SCORER opts in to posting better sentences earlier, either "heuristic" to score
+	// sentences automatically or the location of manual ratings as written by `ffcorpus score`.
+	if scoring := os.Getenv("SKIPPALENIK_SCORER"); scoring != "" {
+		scorer, err := forensicfilescorpus.NewScorer(corpus, scoring)
+		if err != nil {
+			return "", err
+		}
+
+		corpus = corpus.WithScorer(scorer)
+	}
+
+	// The shuffle bag makes sure every sentence is posted once before any is posted again. Lambda
+	// only keeps /tmp while the function is warm, so SKIPPALENIK_BAG should be an s3:// URL for the
+	// bag to last between cold starts.
+	bag := os.Getenv("SKIPPALENIK_BAG")
+	if bag == "" {
+		bag = "/tmp/skippalenik-bag.json"
+	}
+
+	store, err := forensicfilescorpus.BagStoreFor(bag)
+	if err != nil {
+		return "", err
+	}
+
+	// The history of posted tweets keeps sentences similar to recent tweets from being posted, as
+	// the corpus holds many sentences repeated with small differences. As with the bag, it should
+	// be an s3:// URL for it to last between cold starts.
+	historyLocation := os.Getenv("SKIPPALENIK_HISTORY")
+	if historyLocation == "" {
+		historyLocation = "/tmp/skippalenik-history.jsonl"
+	}
+
+	history, err := forensicfilescorpus.HistoryStoreFor(historyLocation)
+	if err != nil {
+		return "", err
+	}
+
+	posted, err := history.Entries()
+	if err != nil {
+		return "", err
+	}
+
+	// Pick excludes sentences at the max length, so allow for a tweet that uses the whole limit.
+	shuffleBag := forensicfilescorpus.NewShuffleBag(corpus, store)
+	pick, err := shuffleBag.Next(forensicfilescorpus.All(
+		forensicfilescorpus.Between(0, forensicfilescorpus.Twitter.Limit+1),
+		forensicfilescorpus.NotRecentlyPosted(posted, time.Now().Add(-forensicfilescorpus.HistoryWindow))))
+	if err != nil {
+		return "", err
+	}
+
+	tweet, _, err := client.Statuses.Update(pick.Text, nil)
 	if err != nil {
 		return "", err
 	}
 
 	url := fmt.Sprintf("https://twitter.com/%v/status/%v", tweet.User.ScreenName, tweet.IDStr)
+
+	// The bag is only saved once the tweet is posted, so a failed tweet is tried again next time.
+	if err := shuffleBag.Commit(); err != nil {
+		return url, err
+	}
+
+	if err := history.Add(forensicfilescorpus.NewHistoryEntry(pick.Text, url, time.Now())); err != nil {
+		return url, err
+	}
+
 	return url, nil
 }
 
 func main() {
+	defaultcorpus.Register()
 	lambda.Start(Handler)
 }
@@ -0,0 +1,197 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CoherentRelated is the number of the most related sentences `Corpus.GenerateCoherent` chooses
+// between when a paragraph can no longer continue within the same episode.
+var CoherentRelated = 5
+
+// GenerateCoherent generates a random paragraph in the same way as `Generate`, except that only the
+// first sentence is picked at random. The paragraph then continues with the sentences that follow
+// it within the same episode, in the order they were kept by `StripSentences`. Sentences without an
+// episode follow each other in the order of the corpus, which is the case for plain text corpora
+// and compiled corpora such as the default corpus.
+//
+// When the episode runs out, or the following sentence does not fit within max, the paragraph
+// continues with one of the sentences sharing the most rare words with the paragraph so far, and
+// carries on from there. Only when no related sentence fits is a sentence picked at random. When no
+// sentence fits at all, the last sentence is taken back out of the paragraph and another is tried
+// in its place. Sentences are never used twice, and a filter given to the corpus with `WithFilter`
+// applies to every sentence. The min and max lengths follow the same rules as `Generate`.
+func (c *Corpus) GenerateCoherent(min, max int) (string, error) {
+	min, max, err := pickRange(min, max, c.unit)
+	if err != nil {
+		return "", err
+	}
+
+	i, err := c.pickIndex(-1, max)
+	if err != nil {
+		return "", err
+	}
+
+	c.nextOnce.Do(c.buildNext)
+
+	paragraph := []int{i}
+	used := map[int]bool{i: true}
+	out := c.sentences[i].Text
+
+	for c.unit.Length(out) <= min {
+		n := -1
+		if len(paragraph) > 0 {
+			n = c.next[paragraph[len(paragraph)-1]]
+		}
+
+		if n < 0 || used[n] || !c.fits(out, n, max) {
+			n = c.related(out, used, max)
+		}
+
+		if n < 0 {
+			n = c.unused(out, used, max)
+		}
+
+		// Sentences taken back out stay used, so every step either uses a new sentence or removes
+		// one, and the paragraph is given up on once every sentence has been tried.
+		if n < 0 {
+			if len(paragraph) == 0 {
+				return "", errors.New("unable to generate a paragraph with given min and max values")
+			}
+
+			paragraph = paragraph[:len(paragraph)-1]
+		} else {
+			paragraph = append(paragraph, n)
+			used[n] = true
+		}
+
+		out = c.join(paragraph)
+	}
+
+	return out, nil
+}
+
+// join returns the text of the sentences at indexes, separated by spaces.
+func (c *Corpus) join(indexes []int) string {
+	texts := make([]string, len(indexes))
+	for n, i := range indexes {
+		texts[n] = c.sentences[i].Text
+	}
+
+	return strings.Join(texts, " ")
+}
+
+// buildNext links each sentence to the sentence following it within the same episode.
+func (c *Corpus) buildNext() {
+	c.next = make([]int, len(c.sentences))
+	episodes := map[string][]int{}
+
+	for i, sentence := range c.sentences {
+		c.next[i] = -1
+
+		if sentence.Episode == "" {
+			if i+1 < len(c.sentences) && c.sentences[i+1].Episode == "" {
+				c.next[i] = i + 1
+			}
+
+			continue
+		}
+
+		episodes[sentence.Episode] = append(episodes[sentence.Episode], i)
+	}
+
+	for _, episode := range episodes {
+		sort.SliceStable(episode, func(a, b int) bool {
+			return c.sentences[episode[a]].Index < c.sentences[episode[b]].Index
+		})
+
+		for n := 1; n < len(episode); n++ {
+			c.next[episode[n-1]] = episode[n]
+		}
+	}
+}
+
+// fits reports whether sentence i can be added to the paragraph out without reaching max, and is
+// accepted by the filter of the corpus.
+func (c *Corpus) fits(out string, i, max int) bool {
+	text := c.sentences[i].Text
+	if out != "" {
+		text = out + " " + text
+	}
+
+	if c.unit.Length(text) >= max {
+		return false
+	}
+
+	return c.filter == nil || c.filter(c.sentences[i])
+}
+
+// related returns one of the unused sentences that fit after out and share the most rare words with
+// it, or -1 if no sentence shares a word. Words are weighted by their inverse document frequency,
+// leaving out `Stopwords`.
+func (c *Corpus) related(out string, used map[int]bool, max int) int {
+	c.indexOnce.Do(c.buildIndex)
+
+	scores := map[int]float64{}
+	seen := map[string]bool{}
+
+	for _, token := range Tokenise(out) {
+		term := c.term(token.Text)
+		if Stopwords[token.Text] || seen[term] {
+			continue
+		}
+
+		seen[term] = true
+		postings := c.index.postings[term]
+		idf := math.Log(float64(len(c.sentences)) / float64(len(postings)))
+
+		for _, p := range postings {
+			scores[p.sentence] += idf
+		}
+	}
+
+	candidates := make([]int, 0, len(scores))
+	for i, score := range scores {
+		if score > 0 && !used[i] && c.fits(out, i, max) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if scores[candidates[a]] != scores[candidates[b]] {
+			return scores[candidates[a]] > scores[candidates[b]]
+		}
+
+		return candidates[a] < candidates[b]
+	})
+
+	if len(candidates) > CoherentRelated {
+		candidates = candidates[:CoherentRelated]
+	}
+
+	return candidates[c.intn(len(candidates))]
+}
+
+// unused returns a random unused sentence that fits after out, or -1 if there are none.
+func (c *Corpus) unused(out string, used map[int]bool, max int) int {
+	found, n := -1, 0
+
+	for i := range c.sentences {
+		if used[i] || !c.fits(out, i, max) {
+			continue
+		}
+
+		n++
+		if c.intn(n) == 0 {
+			found = i
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,271 @@
+package forensicfilescorpus
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// splitParagraph splits a paragraph of sentences that end with a full stop back into sentences.
+func splitParagraph(out string) []string {
+	sentences := strings.SplitAfter(out, ". ")
+	for i := range sentences {
+		sentences[i] = strings.TrimSpace(sentences[i])
+	}
+
+	return sentences
+}
+
+var coherentSentences = []Sentence{
+	{Text: "Carol drove to the lake.", Episode: "a", Index: 2},
+	{Text: "Police found her car.", Episode: "b", Index: 0},
+	{Text: "Anna left work early.", Episode: "a", Index: 0},
+	{Text: "Divers searched the water.", Episode: "b", Index: 1},
+	{Text: "She was never seen again.", Episode: "a", Index: 3},
+	{Text: "Bob met her at the station.", Episode: "a", Index: 1},
+	{Text: "Nothing was recovered.", Episode: "b", Index: 2},
+}
+
+func TestGenerateCoherentFollowsEpisodes(t *testing.T) {
+	following := map[string]string{}
+	for _, episode := range [][]int{{2, 5, 0, 4}, {1, 3, 6}} {
+		for n := 1; n < len(episode); n++ {
+			following[coherentSentences[episode[n-1]].Text] = coherentSentences[episode[n]].Text
+		}
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		corpus := NewCorpusFromSentences(coherentSentences, WithSource(rand.NewSource(seed)))
+
+		out, err := corpus.GenerateCoherent(100, -1)
+		if err != nil {
+			t.Fatalf("seed %d: GenerateCoherent(100, -1) error = %v", seed, err)
+		}
+
+		sentences := splitParagraph(out)
+		seen := map[string]bool{}
+
+		for n, sentence := range sentences {
+			if seen[sentence] {
+				t.Errorf("seed %d: GenerateCoherent() = %q, uses %q twice", seed, out, sentence)
+			}
+
+			// The following sentence is used whenever it has not been used already.
+			if n > 0 {
+				want, ok := following[sentences[n-1]]
+				if ok && !seen[want] && sentence != want {
+					t.Errorf("seed %d: GenerateCoherent() = %q, want %q after %q", seed, out, want, sentences[n-1])
+				}
+			}
+
+			seen[sentence] = true
+		}
+	}
+}
+
+func TestGenerateCoherentWithoutEpisodes(t *testing.T) {
+	texts := []string{"Anna left work early.", "Bob met her at the station.", "Carol drove to the lake."}
+
+	for seed := int64(0); seed < 10; seed++ {
+		corpus := NewCorpus(texts, WithSource(rand.NewSource(seed)))
+
+		out, err := corpus.GenerateCoherent(30, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sentences := splitParagraph(out)
+		if len(sentences) < 2 {
+			t.Fatalf("seed %d: GenerateCoherent(30, -1) = %q, want at least two sentences", seed, out)
+		}
+
+		// Without episodes the sentences follow the order of the corpus.
+		for n := 1; n < len(sentences); n++ {
+			for i, text := range texts[:len(texts)-1] {
+				if sentences[n-1] == text && sentences[n] != texts[i+1] {
+					t.Errorf("seed %d: GenerateCoherent() = %q, want %q after %q", seed, out, texts[i+1], text)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateCoherentLengths(t *testing.T) {
+	texts := []string{"Alpha went home.", "Bravo ate lunch.", "Charlie slept late."}
+	unrelated := make([]Sentence, len(texts))
+	for i, text := range texts {
+		unrelated[i] = Sentence{Text: text, Episode: text}
+	}
+
+	tests := []struct {
+		name     string
+		min, max int
+		count    int
+		err      string
+	}{
+		{"one sentence", -1, -1, 1, ""},
+		{"every sentence", 45, -1, 3, ""},
+		{"within max", 19, 40, 2, ""},
+		{"not enough sentences", 60, -1, 0, "unable to generate a paragraph with given min and max values"},
+		{"max too small for two", 19, 33, 0, "unable to generate a paragraph with given min and max values"},
+		{"min larger than max", 50, 40, 0, "min value must be smaller than max"},
+		{"max below the minimum", -1, MinimumLineLength - 1, 0, "max value must be larger than the minimum sentence length"},
+	}
+
+	for _, test := range tests {
+		for seed := int64(0); seed < 10; seed++ {
+			corpus := NewCorpusFromSentences(unrelated, WithSource(rand.NewSource(seed)))
+			out, err := corpus.GenerateCoherent(test.min, test.max)
+
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("%s: GenerateCoherent(%d, %d) = %q, %v, want error %q", test.name, test.min, test.max, out, err, test.err)
+				}
+
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("%s: GenerateCoherent(%d, %d) error = %v", test.name, test.min, test.max, err)
+				continue
+			}
+
+			sentences := splitParagraph(out)
+			if len(sentences) != test.count {
+				t.Errorf("%s: GenerateCoherent(%d, %d) = %q, want %d sentences", test.name, test.min, test.max, out, test.count)
+			}
+
+			for _, text := range texts {
+				if strings.Count(out, text) > 1 {
+					t.Errorf("%s: GenerateCoherent(%d, %d) = %q, uses %q twice", test.name, test.min, test.max, out, text)
+				}
+			}
+
+			length := DefaultUnit.Length(out)
+			if length <= test.min || test.max >= 0 && length >= test.max {
+				t.Errorf("%s: GenerateCoherent(%d, %d) = %q with length %d", test.name, test.min, test.max, out, length)
+			}
+		}
+	}
+}
+
+func TestGenerateCoherentBacktracks(t *testing.T) {
+	// A paragraph starting with the first sentence cannot fit another sentence, so it has to be
+	// taken back out and the paragraph started again from the other two.
+	texts := []string{"Nobody saw him leave", "He ran on.", "She slept."}
+	want := []string{"He ran on. She slept.", "She slept. He ran on."}
+
+	for seed := int64(0); seed < 20; seed++ {
+		corpus := NewCorpus(texts, WithSource(rand.NewSource(seed)))
+
+		out, err := corpus.GenerateCoherent(20, 24)
+		if err != nil || !containsString(want, out) {
+			t.Errorf("seed %d: GenerateCoherent(20, 24) = %q, %v, want one of %q", seed, out, err, want)
+		}
+	}
+}
+
+func TestGenerateCoherentFilter(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		corpus := NewCorpusFromSentences(coherentSentences, WithSource(rand.NewSource(seed)),
+			WithFilter(Excludes("her")))
+
+		out, err := corpus.GenerateCoherent(60, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, sentence := range splitParagraph(out) {
+			if strings.Contains(sentence, " her ") || strings.HasSuffix(sentence, " her car.") {
+				t.Errorf("seed %d: GenerateCoherent() = %q, want no sentences mentioning her", seed, out)
+			}
+		}
+	}
+}
+
+func TestCoherentRelated(t *testing.T) {
+	defer func(related int) { CoherentRelated = related }(CoherentRelated)
+	CoherentRelated = 1
+
+	texts := []string{
+		"The barn burned down overnight.",
+		"Firemen found the barn empty.",
+		"Nobody saw the tractor.",
+		"The barn owner was arrested.",
+		"The owner of the tractor left overnight.",
+	}
+
+	corpus := NewCorpus(texts, WithSource(rand.NewSource(1)))
+	out := texts[0] + " " + texts[1]
+
+	tests := []struct {
+		name string
+		used []int
+		max  int
+		want int
+	}{
+		{"most shared rare words", []int{0, 1}, 1000, 4},
+		{"skips used sentences", []int{0, 1, 4}, 1000, 3},
+		{"skips sentences that do not fit", []int{0, 1}, len(out) + 30, 3},
+		{"no shared words", []int{0, 1, 3, 4}, len(out) + 30, -1},
+	}
+
+	for _, test := range tests {
+		used := map[int]bool{}
+		for _, i := range test.used {
+			used[i] = true
+		}
+
+		if got := corpus.related(out, used, test.max); got != test.want {
+			t.Errorf("%s: related() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCoherentUnused(t *testing.T) {
+	corpus := NewCorpus(testSentences, WithSource(rand.NewSource(1)))
+
+	tests := []struct {
+		name string
+		out  string
+		used []int
+		max  int
+		want []string
+	}{
+		{"everything fits", "", nil, 100, testSentences},
+		{"skips used sentences", "", []int{0, 1, 2, 3}, 100, nil},
+		{"only short sentences fit", "Hello.", nil, 26, []string{"He ran away.", "The van was found.", "Is this the man?"}},
+		{"nothing fits", "Hello.", nil, 10, nil},
+	}
+
+	for _, test := range tests {
+		used := map[int]bool{}
+		for _, i := range test.used {
+			used[i] = true
+		}
+
+		want := test.want
+		if want == nil && len(test.used) > 0 {
+			for i, sentence := range corpus.sentences {
+				if !used[i] {
+					want = append(want, sentence.Text)
+				}
+			}
+		}
+
+		for n := 0; n < 20; n++ {
+			got := corpus.unused(test.out, used, test.max)
+			if want == nil {
+				if got != -1 {
+					t.Errorf("%s: unused() = %d, want -1", test.name, got)
+				}
+
+				continue
+			}
+
+			if got < 0 || !containsString(want, corpus.sentences[got].Text) {
+				t.Errorf("%s: unused() = %d, want one of %q", test.name, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,612 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// CompiledMagic is found at the start of every compiled corpus file.
+const CompiledMagic = "FFC1"
+
+// CompiledVersion is the version of the compiled corpus format written by `WriteCompiled`.
+const CompiledVersion = 1
+
+// The sections of a compiled corpus, in the order they appear within the file. The header holds the
+// offset of each section from the start of the file.
+const (
+	sectionOffsets = iota // uint64 offset of each sentence within the data section, plus the end
+	sectionLengths        // uint32 length of each sentence, measured in the compiled unit
+	sectionWords          // uint32 word count of each sentence
+	sectionEnds           // uint8 end punctuation of each sentence, see `endCodes`
+	sectionOrder          // uint32 sentence indexes ordered by length
+	sectionSorted         // uint32 sentence lengths ordered by length
+	sectionData           // the text of every sentence, one after another
+	sectionCount
+)
+
+// compiledHeader is the fixed size header at the start of a compiled corpus.
+type compiledHeader struct {
+	Magic    [4]byte
+	Version  uint32
+	Count    uint64
+	Unit     [16]byte
+	Sections [sectionCount]uint64
+}
+
+var compiledHeaderSize = binary.Size(compiledHeader{})
+
+// endCodes are the end punctuation stored within the ends section. Zero is no end punctuation.
+var endCodes = []string{"", ".", "?", "!", "…", `"`, "”"}
+
+// WriteCompiled writes a corpus to w in the compiled corpus format. The compiled format holds an
+// offset for every sentence along with the length and metadata columns, so that a reader can pick
+// a sentence by seeking to it rather than loading the whole file. See `OpenCompiled`.
+//
+// The episode, index, speaker and timecodes of each sentence are not stored. A corpus loaded from a
+// compiled file keeps the order of the sentences instead, which is the order
+// `Corpus.GenerateCoherent` follows. The default corpus is compiled from the plain text
+// sentences.txt, which holds no metadata to begin with.
+func WriteCompiled(w io.Writer, corpus *Corpus) error {
+	if len(corpus.unit.Name) > 16 {
+		return errors.New("unit name too long for compiled corpus")
+	}
+
+	count := uint64(corpus.Len())
+	header := compiledHeader{Version: CompiledVersion, Count: count}
+	copy(header.Magic[:], CompiledMagic)
+	copy(header.Unit[:], corpus.unit.Name)
+
+	sizes := [sectionCount]uint64{
+		sectionOffsets: (count + 1) * 8,
+		sectionLengths: count * 4,
+		sectionWords:   count * 4,
+		sectionEnds:    count,
+		sectionOrder:   count * 4,
+		sectionSorted:  count * 4,
+	}
+
+	offset := uint64(compiledHeaderSize)
+	for section := range header.Sections {
+		header.Sections[section] = offset
+		offset += sizes[section]
+	}
+
+	out := bufio.NewWriter(w)
+	write := func(v interface{}) error {
+		return binary.Write(out, binary.LittleEndian, v)
+	}
+
+	if err := write(header); err != nil {
+		return err
+	}
+
+	var position uint64
+	for _, sentence := range corpus.sentences {
+		if err := write(position); err != nil {
+			return err
+		}
+		position += uint64(len(sentence.Text))
+	}
+
+	if err := write(position); err != nil {
+		return err
+	}
+
+	for _, sentence := range corpus.sentences {
+		if err := write(uint32(sentence.Length)); err != nil {
+			return err
+		}
+	}
+
+	for _, sentence := range corpus.sentences {
+		if err := write(uint32(sentence.Words)); err != nil {
+			return err
+		}
+	}
+
+	for _, sentence := range corpus.sentences {
+		if err := write(endCode(sentence.End)); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range corpus.byLength {
+		if err := write(uint32(n)); err != nil {
+			return err
+		}
+	}
+
+	for _, length := range corpus.lengths {
+		if err := write(uint32(length)); err != nil {
+			return err
+		}
+	}
+
+	for _, sentence := range corpus.sentences {
+		if _, err := out.WriteString(sentence.Text); err != nil {
+			return err
+		}
+	}
+
+	return out.Flush()
+}
+
+func endCode(end string) uint8 {
+	for code, e := range endCodes {
+		if e == end {
+			return uint8(code)
+		}
+	}
+
+	return 0
+}
+
+// CompileFile loads the corpus found at src and writes it to dest in the compiled format. The
+// options are used to load the corpus, and the unit chosen is the unit stored within the file.
+func CompileFile(src, dest string, options ...Option) error {
+	corpus, err := LoadCorpus(src, options...)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteCompiled(out, corpus); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// CompiledCorpus reads sentences from a compiled corpus on demand, without loading the file into
+// memory. Picking a sentence takes a binary search over the lengths section followed by a single
+// read of the chosen sentence. A compiled corpus is safe for concurrent use as long as the
+// underlying reader is, which is the case for files and `bytes.Reader`.
+type CompiledCorpus struct {
+	r      io.ReaderAt
+	closer io.Closer
+	header compiledHeader
+	rng    *rand.Rand
+	filter Filter
+
+	// compiled is the unit the lengths section was measured in, and unit is the unit picks are
+	// measured in. These only differ when the corpus is opened with `WithUnit`.
+	compiled Unit
+	unit     Unit
+
+	// data is the size of the data section, which every sentence offset must be within.
+	data uint64
+}
+
+// IsCompiled reports whether the file at path is a compiled corpus, which may be compressed. An
+// empty path is the default corpus, see `RegisterDefault`.
+func IsCompiled(path string) bool {
+	if path == "" {
+		return HasDefault()
+	}
+
+	src, err := OpenFile(path)
+	if err != nil {
+		return false
+	}
+
+	defer src.Close()
+
+	magic := make([]byte, len(CompiledMagic))
+	if _, err := io.ReadFull(src, magic); err != nil {
+		return false
+	}
+
+	return string(magic) == CompiledMagic
+}
+
+// OpenCompiled opens the compiled corpus at path. The corpus must be closed once finished with.
+// Options are used for the source of randomisation and the unit. Picks are measured in the unit
+// the corpus was compiled with, unless another unit is given with `WithUnit`, in which case every
+// sentence must be read and measured on each pick. A compressed compiled corpus cannot be read by
+// seeking, so it is decompressed into memory instead. An empty path opens the default corpus, see
+// `RegisterDefault`.
+func OpenCompiled(path string, options ...Option) (*CompiledCorpus, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		if !HasDefault() {
+			return nil, errNoDefault
+		}
+
+		return NewCompiledCorpus(defaultCorpus, options...)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 4)
+	n, _ := io.ReadFull(src, header)
+
+	if DetectCompression(header[:n]) != NoCompression {
+		src.Close()
+		return openCompressedCompiled(path, options)
+	}
+
+	corpus, err := NewCompiledCorpus(src, options...)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	corpus.closer = src
+	return corpus, nil
+}
+
+func openCompressedCompiled(path string, options []Option) (*CompiledCorpus, error) {
+	src, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCompiledCorpus(bytes.NewReader(data), options...)
+}
+
+// NewCompiledCorpus reads a compiled corpus from r. The header is checked against the size of r, so
+// a truncated or corrupt corpus is reported here rather than when a sentence is read.
+func NewCompiledCorpus(r io.ReaderAt, options ...Option) (*CompiledCorpus, error) {
+	config := configure(options)
+	c := &CompiledCorpus{r: r, rng: config.rng, filter: config.filter}
+
+	buf := make([]byte, compiledHeaderSize)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		return nil, errors.New("unable to read compiled corpus header")
+	}
+
+	if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &c.header); err != nil {
+		return nil, err
+	}
+
+	if string(c.header.Magic[:]) != CompiledMagic {
+		return nil, errors.New("not a compiled corpus")
+	}
+
+	if c.header.Version != CompiledVersion {
+		return nil, errors.New("unsupported compiled corpus version")
+	}
+
+	unit, err := UnitByName(string(bytes.TrimRight(c.header.Unit[:], "\x00")))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	c.compiled = unit
+	c.unit = unit
+
+	if config.unitSet {
+		c.unit = config.unit
+	}
+
+	return c, nil
+}
+
+// errCorruptCompiled is returned when a compiled corpus does not match its header.
+var errCorruptCompiled = errors.New("compiled corpus is corrupt or truncated")
+
+// maxCompiledCount is the most sentences a compiled corpus can hold, which keeps the size of every
+// section well within an int64.
+const maxCompiledCount = 1 << 40
+
+// validate checks that the sections are laid out one after another as `WriteCompiled` writes them,
+// that the sentence offsets start at zero, and that the data section is within r.
+func (c *CompiledCorpus) validate() error {
+	count := c.header.Count
+	if count > maxCompiledCount {
+		return errCorruptCompiled
+	}
+
+	sizes := [sectionCount]uint64{
+		sectionOffsets: (count + 1) * 8,
+		sectionLengths: count * 4,
+		sectionWords:   count * 4,
+		sectionEnds:    count,
+		sectionOrder:   count * 4,
+		sectionSorted:  count * 4,
+	}
+
+	offset := uint64(compiledHeaderSize)
+	for section, start := range c.header.Sections {
+		if start != offset {
+			return errCorruptCompiled
+		}
+
+		offset += sizes[section]
+	}
+
+	var offsets [8]byte
+	if _, err := c.r.ReadAt(offsets[:], int64(c.header.Sections[sectionOffsets])); err != nil {
+		return errCorruptCompiled
+	}
+
+	if binary.LittleEndian.Uint64(offsets[:]) != 0 {
+		return errCorruptCompiled
+	}
+
+	if _, err := c.r.ReadAt(offsets[:], int64(c.header.Sections[sectionOffsets]+count*8)); err != nil {
+		return errCorruptCompiled
+	}
+
+	c.data = binary.LittleEndian.Uint64(offsets[:])
+	if c.data > math.MaxInt64-offset {
+		return errCorruptCompiled
+	}
+
+	end := int64(offset + c.data)
+
+	if size, ok := readerSize(c.r); ok {
+		if end > size {
+			return errCorruptCompiled
+		}
+
+		return nil
+	}
+
+	if c.data > 0 {
+		var last [1]byte
+		if _, err := c.r.ReadAt(last[:], end-1); err != nil {
+			return errCorruptCompiled
+		}
+	}
+
+	return nil
+}
+
+// readerSize returns the size of r, if r is able to report it.
+func readerSize(r io.ReaderAt) (int64, bool) {
+	switch r := r.(type) {
+	case interface{ Size() int64 }:
+		return r.Size(), true
+	case interface{ Stat() (os.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil {
+			return info.Size(), true
+		}
+	}
+
+	return 0, false
+}
+
+// Close closes the file the corpus was opened from, if any.
+func (c *CompiledCorpus) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+
+	return c.closer.Close()
+}
+
+// Len returns the number of sentences within the corpus.
+func (c *CompiledCorpus) Len() int {
+	return int(c.header.Count)
+}
+
+// Unit returns the unit picks from the corpus are measured in.
+func (c *CompiledCorpus) Unit() Unit {
+	return c.unit
+}
+
+func (c *CompiledCorpus) readUint32(section, i int) (uint32, error) {
+	var buf [4]byte
+	if _, err := c.r.ReadAt(buf[:], int64(c.header.Sections[section])+int64(i)*4); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+// Sentence reads the sentence at index i.
+func (c *CompiledCorpus) Sentence(i int) (Sentence, error) {
+	if i < 0 || i >= c.Len() {
+		return Sentence{}, errors.New("sentence index out of range")
+	}
+
+	var offsets [16]byte
+	if _, err := c.r.ReadAt(offsets[:], int64(c.header.Sections[sectionOffsets])+int64(i)*8); err != nil {
+		return Sentence{}, err
+	}
+
+	start := binary.LittleEndian.Uint64(offsets[:8])
+	end := binary.LittleEndian.Uint64(offsets[8:])
+
+	if start > end || end > c.data {
+		return Sentence{}, errCorruptCompiled
+	}
+
+	text := make([]byte, end-start)
+	if _, err := c.r.ReadAt(text, int64(c.header.Sections[sectionData]+start)); err != nil {
+		return Sentence{}, err
+	}
+
+	length, err := c.readUint32(sectionLengths, i)
+	if err != nil {
+		return Sentence{}, err
+	}
+
+	words, err := c.readUint32(sectionWords, i)
+	if err != nil {
+		return Sentence{}, err
+	}
+
+	var code [1]byte
+	if _, err := c.r.ReadAt(code[:], int64(c.header.Sections[sectionEnds])+int64(i)); err != nil {
+		return Sentence{}, err
+	}
+
+	sentence := Sentence{Text: string(text), Length: int(length), Words: int(words)}
+	if int(code[0]) < len(endCodes) {
+		sentence.End = endCodes[code[0]]
+	}
+
+	return sentence, nil
+}
+
+// Pick a random sentence from the compiled corpus, with the same min and max rules as `Pick`.
+func (c *CompiledCorpus) Pick(min, max int) (string, error) {
+	if c.Len() == 0 {
+		return "", errors.New("unable to pick from empty sentences slice")
+	}
+
+	min, max, err := pickRange(min, max, c.unit)
+	if err != nil {
+		return "", err
+	}
+
+	if c.unit.Name != c.compiled.Name {
+		return c.scanPick(min, max)
+	}
+
+	search := func(length int) int {
+		return sort.Search(c.Len(), func(i int) bool {
+			n, e := c.readUint32(sectionSorted, i)
+			if e != nil {
+				err = e
+				return true
+			}
+			return int(n) >= length
+		})
+	}
+
+	lo, hi := search(min+1), search(max)
+	if err != nil {
+		return "", err
+	}
+
+	if lo >= hi {
+		return "", errNoCandidates
+	}
+
+	if c.filter != nil {
+		return c.pickFiltered(lo, hi)
+	}
+
+	n, err := c.readUint32(sectionOrder, lo+intn(c.rng, hi-lo))
+	if err != nil {
+		return "", err
+	}
+
+	sentence, err := c.Sentence(int(n))
+	if err != nil {
+		return "", err
+	}
+
+	return sentence.Text, nil
+}
+
+// pickFiltered picks a sentence accepted by the filter of the corpus from the sentences at lo to hi
+// within the length order, reading each of them in turn.
+func (c *CompiledCorpus) pickFiltered(lo, hi int) (string, error) {
+	chosen := ""
+	seen := 0
+
+	for i := lo; i < hi; i++ {
+		n, err := c.readUint32(sectionOrder, i)
+		if err != nil {
+			return "", err
+		}
+
+		sentence, err := c.Sentence(int(n))
+		if err != nil {
+			return "", err
+		}
+
+		if !c.filter(sentence) {
+			continue
+		}
+
+		seen++
+		if intn(c.rng, seen) == 0 {
+			chosen = sentence.Text
+		}
+	}
+
+	if seen == 0 {
+		return "", errNoMatch
+	}
+
+	return chosen, nil
+}
+
+// scanPick picks a sentence by reading and measuring every sentence, for when the corpus is opened
+// with a different unit to the one it was compiled with.
+func (c *CompiledCorpus) scanPick(min, max int) (string, error) {
+	filter := All(Between(min, max), c.filter)
+	measure := &Corpus{corpusData: corpusData{unit: c.unit}}
+	chosen := ""
+	seen := 0
+
+	for i := 0; i < c.Len(); i++ {
+		sentence, err := c.Sentence(i)
+		if err != nil {
+			return "", err
+		}
+
+		if !filter(measure.measure(sentence)) {
+			continue
+		}
+
+		seen++
+		if intn(c.rng, seen) == 0 {
+			chosen = sentence.Text
+		}
+	}
+
+	if seen == 0 {
+		return "", errNoCandidates
+	}
+
+	return chosen, nil
+}
+
+// Generate a random paragraph from the compiled corpus, with the same rules as `Generate`.
+func (c *CompiledCorpus) Generate(min, max int) (string, error) {
+	return generate(c.Pick, c.unit, min, max)
+}
+
+// Load reads every sentence within the compiled corpus into memory. The loaded corpus is measured
+// in the same unit, unless options give another.
+func (c *CompiledCorpus) Load(options ...Option) (*Corpus, error) {
+	sentences := make([]string, c.Len())
+
+	for i := range sentences {
+		sentence, err := c.Sentence(i)
+		if err != nil {
+			return nil, err
+		}
+
+		sentences[i] = sentence.Text
+	}
+
+	return NewCorpus(sentences, append([]Option{WithUnit(c.unit)}, options...)...), nil
+}
@@ -0,0 +1,163 @@
+package forensicfilescorpus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"testing"
+)
+
+func compileTestCorpus(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := WriteCompiled(&buf, NewCorpus(testSentences)); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestCompiledRoundTrip(t *testing.T) {
+	corpus := NewCorpus(testSentences)
+
+	compiled, err := NewCompiledCorpus(bytes.NewReader(compileTestCorpus(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if compiled.Len() != corpus.Len() || compiled.Unit().Name != corpus.Unit().Name {
+		t.Fatalf("compiled corpus has %d sentences in %s, want %d in %s", compiled.Len(), compiled.Unit().Name, corpus.Len(), corpus.Unit().Name)
+	}
+
+	for i := 0; i < corpus.Len(); i++ {
+		got, err := compiled.Sentence(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := corpus.Sentence(i)
+		if got.Text != want.Text || got.Length != want.Length || got.Words != want.Words || got.End != want.End {
+			t.Errorf("Sentence(%d) = %+v, want %+v", i, got, want)
+		}
+	}
+
+	tests := []struct {
+		min, max int
+		want     []string
+	}{
+		{12, 31, []string{"The van was found.", "Is this the man?"}},
+		{30, -1, []string{"Police searched the river bank.", "Fibers were found on the victim's coat."}},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 20; i++ {
+			got, err := compiled.Pick(test.min, test.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !containsString(test.want, got) {
+				t.Errorf("Pick(%d, %d) = %q, want one of %q", test.min, test.max, got, test.want)
+			}
+		}
+	}
+
+	if _, err := compiled.Pick(39, -1); err != errNoCandidates {
+		t.Errorf("Pick(39, -1) error = %v, want %v", err, errNoCandidates)
+	}
+}
+
+func TestCompileFile(t *testing.T) {
+	dir := t.TempDir()
+	src := writeSentences(t, dir, "sentences.txt", testSentences)
+	dest := filepath.Join(dir, "corpus.ffc")
+
+	if err := CompileFile(src, dest, WithUnit(Words)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsCompiled(dest) || IsCompiled(src) {
+		t.Errorf("IsCompiled() = %t for the compiled file and %t for the text file", IsCompiled(dest), IsCompiled(src))
+	}
+
+	compiled, err := OpenCompiled(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer compiled.Close()
+
+	if compiled.Unit().Name != Words.Name {
+		t.Errorf("OpenCompiled() unit = %s, want %s", compiled.Unit().Name, Words.Name)
+	}
+}
+
+func TestCompiledCorrupt(t *testing.T) {
+	valid := compileTestCorpus(t)
+	offsets := int(binary.LittleEndian.Uint64(valid[compiledHeaderSize-sectionCount*8:]))
+
+	setUint64 := func(at int, v uint64) func([]byte) []byte {
+		return func(data []byte) []byte {
+			binary.LittleEndian.PutUint64(data[at:], v)
+			return data
+		}
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func([]byte) []byte
+	}{
+		{"empty", func(data []byte) []byte { return nil }},
+		{"header only", func(data []byte) []byte { return data[:compiledHeaderSize] }},
+		{"truncated data", func(data []byte) []byte { return data[:len(data)-1] }},
+		{"truncated offsets", func(data []byte) []byte { return data[:offsets+8] }},
+		{"bad magic", func(data []byte) []byte { data[0] = 'X'; return data }},
+		{"huge count", setUint64(8, 1<<62)},
+		{"count past sections", setUint64(8, uint64(len(testSentences)+1))},
+		{"section out of order", setUint64(compiledHeaderSize-8, 0)},
+		{"first offset", setUint64(offsets, 1)},
+		{"data past end", setUint64(offsets+len(testSentences)*8, 1<<40)},
+	}
+
+	for _, test := range tests {
+		data := test.corrupt(append([]byte{}, valid...))
+
+		if _, err := NewCompiledCorpus(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: NewCompiledCorpus() returned no error", test.name)
+		}
+	}
+}
+
+func TestCompiledCorruptSentence(t *testing.T) {
+	data := compileTestCorpus(t)
+	offsets := int(binary.LittleEndian.Uint64(data[compiledHeaderSize-sectionCount*8:]))
+
+	// The second sentence ends before it starts, and the third starts past the data section.
+	binary.LittleEndian.PutUint64(data[offsets+2*8:], 1)
+	binary.LittleEndian.PutUint64(data[offsets+3*8:], 1<<40)
+
+	compiled, err := NewCompiledCorpus(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		index int
+		err   bool
+	}{
+		{0, false},
+		{1, true},
+		{2, true},
+		{3, true},
+		{4, false},
+		{5, true},
+		{-1, true},
+	}
+
+	for _, test := range tests {
+		if _, err := compiled.Sentence(test.index); (err != nil) != test.err {
+			t.Errorf("Sentence(%d) error = %v, want error %t", test.index, err, test.err)
+		}
+	}
+}
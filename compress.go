@@ -0,0 +1,197 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is a compression format a corpus file can be stored in.
+type Compression int
+
+const (
+	// NoCompression is an uncompressed file.
+	NoCompression Compression = iota
+	// Gzip is a gzip compressed file.
+	Gzip
+	// Zstd is a zstd compressed file, which is smaller and faster to read than gzip.
+	Zstd
+)
+
+// compressionMagic holds the bytes each compression format starts with.
+var compressionMagic = map[Compression][]byte{
+	Gzip: {0x1f, 0x8b},
+	Zstd: {0x28, 0xb5, 0x2f, 0xfd},
+}
+
+// compressionExtensions holds the file extension for each compression format.
+var compressionExtensions = map[Compression]string{
+	Gzip: ".gz",
+	Zstd: ".zst",
+}
+
+// ParseCompression returns the compression with the given name, such as "gzip", "zstd" or "none".
+func ParseCompression(name string) (Compression, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return NoCompression, nil
+	case "gzip", "gz":
+		return Gzip, nil
+	case "zstd", "zst":
+		return Zstd, nil
+	}
+
+	return NoCompression, errors.New("unknown compression")
+}
+
+// DetectCompression returns the compression format of data from its first few bytes.
+func DetectCompression(header []byte) Compression {
+	for compression, magic := range compressionMagic {
+		if bytes.HasPrefix(header, magic) {
+			return compression
+		}
+	}
+
+	return NoCompression
+}
+
+// CompressionOf returns the compression format of path from its extension, such as ".gz".
+func CompressionOf(path string) Compression {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	for compression, e := range compressionExtensions {
+		if e == ext {
+			return compression
+		}
+	}
+
+	return NoCompression
+}
+
+// trimCompressionExt removes the compression extension from path, so "sentences.jsonl.gz" becomes
+// "sentences.jsonl".
+func trimCompressionExt(path string) string {
+	if CompressionOf(path) == NoCompression {
+		return path
+	}
+
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+// Decompress wraps r so that reading from it returns the decompressed data. The compression format
+// is detected from the first few bytes, and uncompressed data is returned as it is.
+func Decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(4)
+
+	switch DetectCompression(header) {
+	case Gzip:
+		return gzip.NewReader(buffered)
+	case Zstd:
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+
+		return zstdReader{decoder}, nil
+	}
+
+	return buffered, nil
+}
+
+// OpenFile opens the file at path for reading, transparently decompressing it if it is compressed.
+func OpenFile(path string) (io.ReadCloser, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return nil, errNoDefault
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := Decompress(src)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	return readCloser{r, src}, nil
+}
+
+// CreateFile creates the file at path for writing, compressing everything written to it with the
+// given compression. The returned writer must be closed to finish writing the file.
+func CreateFile(path string, compression Compression) (io.WriteCloser, error) {
+	if _, ok := compressionMagic[compression]; !ok && compression != NoCompression {
+		return nil, errors.New("unknown compression")
+	}
+
+	dest, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch compression {
+	case Gzip:
+		return writeCloser{gzip.NewWriter(dest), dest}, nil
+	case Zstd:
+		encoder, err := zstd.NewWriter(dest)
+		if err != nil {
+			dest.Close()
+			return nil, err
+		}
+
+		return writeCloser{encoder, dest}, nil
+	}
+
+	return dest, nil
+}
+
+// zstdReader adapts a zstd decoder to io.Closer, so closing it releases the decoder.
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	file *os.File
+}
+
+func (r readCloser) Close() error {
+	if closer, ok := r.Reader.(io.Closer); ok {
+		closer.Close()
+	}
+
+	return r.file.Close()
+}
+
+type writeCloser struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (w writeCloser) Close() error {
+	if err := w.WriteCloser.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	return w.file.Close()
+}
@@ -0,0 +1,202 @@
+package forensicfilescorpus
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Compression
+		wantErr bool
+	}{
+		{"", NoCompression, false},
+		{"none", NoCompression, false},
+		{"gzip", Gzip, false},
+		{"GZ", Gzip, false},
+		{"zstd", Zstd, false},
+		{"zst", Zstd, false},
+		{"bzip2", NoCompression, true},
+		{"lz4", NoCompression, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseCompression(test.name)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseCompression(%q) error = %v, want error %v", test.name, err, test.wantErr)
+			continue
+		}
+
+		if got != test.want {
+			t.Errorf("ParseCompression(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCompressionOf(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    Compression
+		trimmed string
+	}{
+		{"sentences.txt", NoCompression, "sentences.txt"},
+		{"sentences.txt.gz", Gzip, "sentences.txt"},
+		{"sentences.jsonl.zst", Zstd, "sentences.jsonl"},
+		{"SENTENCES.TXT.ZST", Zstd, "SENTENCES.TXT"},
+		{"sentences.txt.bz2", NoCompression, "sentences.txt.bz2"},
+	}
+
+	for _, test := range tests {
+		if got := CompressionOf(test.path); got != test.want {
+			t.Errorf("CompressionOf(%q) = %v, want %v", test.path, got, test.want)
+		}
+
+		if got := trimCompressionExt(test.path); got != test.trimmed {
+			t.Errorf("trimCompressionExt(%q) = %q, want %q", test.path, got, test.trimmed)
+		}
+	}
+}
+
+func TestDetectCompression(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   Compression
+	}{
+		{"empty", nil, NoCompression},
+		{"text", []byte("He ran away."), NoCompression},
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00}, Gzip},
+		{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, Zstd},
+		{"truncated zstd", []byte{0x28, 0xb5}, NoCompression},
+		{"bzip2", []byte("BZh9"), NoCompression},
+	}
+
+	for _, test := range tests {
+		if got := DetectCompression(test.header); got != test.want {
+			t.Errorf("%s: DetectCompression() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCompressedFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Join(testSentences, "\n") + "\n"
+
+	tests := []struct {
+		name        string
+		compression Compression
+	}{
+		{"sentences.txt", NoCompression},
+		{"sentences.txt.gz", Gzip},
+		{"sentences.txt.zst", Zstd},
+		// The format is detected from the content, so a misleading extension is still read.
+		{"sentences.zstd.txt", Zstd},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+
+		dest, err := CreateFile(path, test.compression)
+		if err != nil {
+			t.Errorf("%s: CreateFile() error = %v", test.name, err)
+			continue
+		}
+
+		if _, err := io.WriteString(dest, content); err != nil {
+			t.Errorf("%s: Write() error = %v", test.name, err)
+		}
+
+		if err := dest.Close(); err != nil {
+			t.Errorf("%s: Close() error = %v", test.name, err)
+		}
+
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := DetectCompression(raw); got != test.compression {
+			t.Errorf("%s: DetectCompression() = %v, want %v", test.name, got, test.compression)
+		}
+
+		src, err := OpenFile(path)
+		if err != nil {
+			t.Errorf("%s: OpenFile() error = %v", test.name, err)
+			continue
+		}
+
+		got, err := ioutil.ReadAll(src)
+		src.Close()
+
+		if err != nil {
+			t.Errorf("%s: ReadAll() error = %v", test.name, err)
+		}
+
+		if string(got) != content {
+			t.Errorf("%s: OpenFile() read %q, want %q", test.name, got, content)
+		}
+	}
+}
+
+func TestCreateFileUnknownCompression(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sentences.txt")
+
+	if _, err := CreateFile(path, Compression(99)); err == nil {
+		t.Errorf("CreateFile(Compression(99)) error = nil, want an error")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("CreateFile(Compression(99)) created %s", path)
+	}
+}
+
+func TestDecompressCorrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00, 0x00}},
+		{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd, 0xff, 0xff, 0xff}},
+	}
+
+	for _, test := range tests {
+		r, err := Decompress(bytes.NewReader(test.data))
+		if err == nil {
+			_, err = ioutil.ReadAll(r)
+		}
+
+		if err == nil {
+			t.Errorf("%s: Decompress() of corrupt data read without an error", test.name)
+		}
+	}
+}
+
+func TestStripAllToCompressedFile(t *testing.T) {
+	dir := t.TempDir()
+	subtitles := writeSubtitles(t, dir, "episode.srt", episodeCues...)
+
+	for _, compression := range []Compression{Gzip, Zstd} {
+		output := filepath.Join(dir, "sentences.txt"+compressionExtensions[compression])
+
+		if err := StripAllToCompressedFile([]string{subtitles}, output, compression); err != nil {
+			t.Errorf("StripAllToCompressedFile(%v) error = %v", compression, err)
+			continue
+		}
+
+		corpus, err := LoadCorpus(output)
+		if err != nil {
+			t.Errorf("LoadCorpus(%s) error = %v", output, err)
+			continue
+		}
+
+		if got := corpus.Len(); got != len(episodeCues) {
+			t.Errorf("LoadCorpus(%s).Len() = %d, want %d", output, got, len(episodeCues))
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// Sentence is a single sentence within a `Corpus`. The length, word count and end punctuation are
+// computed once when the corpus is loaded. The remaining metadata is kept from `StripSentences`
+// and is only available when the corpus is stored as JSON Lines, see `WriteJSONLines`.
+type Sentence struct {
+	Text   string `json:"text"`
+	Length int    `json:"-"`
+	Words  int    `json:"-"`
+	End    string `json:"-"`
+
+	Episode string   `json:"episode,omitempty"`
+	Index   int      `json:"index,omitempty"`
+	Speaker string   `json:"speaker,omitempty"`
+	Start   Timecode `json:"start,omitempty"`
+	Stop    Timecode `json:"stop,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// Corpus is a collection of sentences loaded into memory once, so that many picks can be made
+// without reading the sentences file each time. A corpus is not modified once it has been created
+// and is safe for concurrent use by multiple goroutines. Picks use the default source for
+// randomisation unless the corpus is created with `WithSource` or `WithRand`.
+type Corpus struct {
+	corpusData
+
+	// index is built from the sentences on the first search, using stem.
+	index     *invertedIndex
+	indexOnce sync.Once
+
+	// weights holds the score of each sentence in length order, and alias samples from them. They
+	// are computed from scorer on the first pick.
+	weights     []float64
+	alias       *AliasSampler
+	aliasErr    error
+	weightsOnce sync.Once
+
+	// next holds the index of the sentence following each sentence, or -1 for the last sentence
+	// of an episode. It is built on the first call to `GenerateCoherent`.
+	next     []int
+	nextOnce sync.Once
+}
+
+// corpusData is everything a `Corpus` is created with, apart from the fields built from it when
+// first needed. It holds no locks, so a corpus can be copied by copying its data and leaving the
+// copy to build everything else again.
+type corpusData struct {
+	unit      Unit
+	unitSet   bool
+	rng       *rand.Rand
+	filter    Filter
+	sentences []Sentence
+
+	// normaliser is only used when stripping subtitle files, see `WithNormaliser`.
+	normaliser Normaliser
+
+	// byLength holds the index of every sentence ordered by length, and lengths holds the length
+	// of each of those sentences in the same order. Together they allow the sentences within a
+	// length range to be found with a binary search rather than a scan.
+	byLength []int
+	lengths  []int
+
+	// stem is used to index sentences for `Search`.
+	stem func(string) string
+
+	// scorer weights picks, using the score of each sentence in length order as the weights of the
+	// sampler.
+	scorer Scorer
+}
+
+// Option configures a `Corpus` when it is created.
+type Option func(*Corpus)
+
+// WithUnit sets the unit used to measure the length of sentences within the corpus. By default
+// a corpus uses `DefaultUnit`.
+func WithUnit(unit Unit) Option {
+	return func(c *Corpus) {
+		c.unit = unit
+		c.unitSet = true
+	}
+}
+
+// NewCorpus creates a corpus from a collection of sentences.
+func NewCorpus(sentences []string, options ...Option) *Corpus {
+	c := configure(options)
+
+	c.sentences = make([]Sentence, len(sentences))
+	for i, text := range sentences {
+		c.sentences[i] = c.newSentence(text)
+	}
+
+	c.indexLengths()
+
+	return c
+}
+
+// NewCorpusFromSentences creates a corpus from sentences that carry metadata, such as those from
+// `StripAllSentences`. The length, word count and end punctuation of each sentence are measured
+// again using the unit of the corpus.
+func NewCorpusFromSentences(sentences []Sentence, options ...Option) *Corpus {
+	c := configure(options)
+
+	c.sentences = make([]Sentence, len(sentences))
+	for i, sentence := range sentences {
+		c.sentences[i] = c.measure(sentence)
+	}
+
+	c.indexLengths()
+
+	return c
+}
+
+// configure creates an empty corpus with the given options applied.
+func configure(options []Option) *Corpus {
+	c := &Corpus{corpusData: corpusData{unit: DefaultUnit, normaliser: DefaultNormaliser()}}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// newSentence measures text using the unit of the corpus.
+func (c *Corpus) newSentence(text string) Sentence {
+	return c.measure(Sentence{Text: text})
+}
+
+// measure sets the length, word count and end punctuation of sentence.
+func (c *Corpus) measure(sentence Sentence) Sentence {
+	sentence.Length = c.unit.Length(sentence.Text)
+	sentence.Words = WordCount(sentence.Text)
+	sentence.End = endPunctuation(sentence.Text)
+	return sentence
+}
+
+// indexLengths orders the sentences by length using a counting sort, as sentence lengths are small
+// and bounded. Sentences of the same length keep the order they appear within the corpus.
+func (c *Corpus) indexLengths() {
+	longest := 0
+	for _, sentence := range c.sentences {
+		if sentence.Length > longest {
+			longest = sentence.Length
+		}
+	}
+
+	offsets := make([]int, longest+2)
+	for _, sentence := range c.sentences {
+		offsets[sentence.Length+1]++
+	}
+
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] += offsets[i-1]
+	}
+
+	c.byLength = make([]int, len(c.sentences))
+	c.lengths = make([]int, len(c.sentences))
+
+	for i, sentence := range c.sentences {
+		n := offsets[sentence.Length]
+		offsets[sentence.Length]++
+		c.byLength[n] = i
+		c.lengths[n] = sentence.Length
+	}
+}
+
+// endPunctuation returns the punctuation matched by `EndToken` at the end of text, or an empty
+// string if the text does not end a sentence.
+func endPunctuation(text string) string {
+	r, size := utf8.DecodeLastRuneInString(text)
+
+	switch r {
+	case '?', '!', '.', '…', '"', '”':
+		return text[len(text)-size:]
+	}
+
+	return ""
+}
+
+// lengthRange returns the positions within byLength of the sentences that are longer than min and
+// shorter than max. The sentences are found at byLength[lo:hi].
+func (c *Corpus) lengthRange(min, max int) (lo, hi int) {
+	lo = sort.SearchInts(c.lengths, min+1)
+	hi = sort.SearchInts(c.lengths, max)
+
+	if hi < lo {
+		hi = lo
+	}
+
+	return lo, hi
+}
+
+// LoadCorpus creates a corpus from the sentences within the file provided by path parameter,
+// where each sentence is on its own line, either as plain text or JSON Lines. Compiled corpus
+// files are also accepted, see `OpenCompiled`, in which case the corpus uses the unit it was
+// compiled with.
+func LoadCorpus(path string, options ...Option) (*Corpus, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsCompiled(path) {
+		compiled, err := OpenCompiled(path)
+		if err != nil {
+			return nil, err
+		}
+
+		defer compiled.Close()
+		return compiled.Load(options...)
+	}
+
+	sentences, err := ReadSentencesFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCorpusFromSentences(sentences, options...), nil
+}
+
+// Len returns the number of sentences within the corpus.
+func (c *Corpus) Len() int {
+	return len(c.sentences)
+}
+
+// Unit returns the unit used to measure the sentences within the corpus.
+func (c *Corpus) Unit() Unit {
+	return c.unit
+}
+
+// Sentence returns the sentence at index i.
+func (c *Corpus) Sentence(i int) Sentence {
+	return c.sentences[i]
+}
+
+// Sentences returns every sentence within the corpus. The returned slice is shared with the
+// corpus and must not be modified.
+func (c *Corpus) Sentences() []Sentence {
+	return c.sentences
+}
+
+// Pick a random sentence from the corpus. A minimum and maximum length for the sentence can be used
+// to filter the sentences, measured in the unit of the corpus. Both are exclusive, and passing a
+// negative value to either one of these will use sensible defaults. Sentences are found using an
+// index by length, so a pick takes O(log n) time and does not allocate. When the corpus is created
+// with `WithFilter` every sentence within the length range is checked against the filter instead,
+// and when it is created with `WithScorer` sentences are picked in proportion to their score.
+func (c *Corpus) Pick(min, max int) (string, error) {
+	i, err := c.pickIndex(min, max)
+	if err != nil {
+		return "", err
+	}
+
+	return c.sentences[i].Text, nil
+}
+
+// pickIndex picks a sentence in the same way as `Pick`, returning the index of the sentence.
+func (c *Corpus) pickIndex(min, max int) (int, error) {
+	if len(c.sentences) == 0 {
+		return -1, errors.New("unable to pick from empty sentences slice")
+	}
+
+	min, max, err := pickRange(min, max, c.unit)
+	if err != nil {
+		return -1, err
+	}
+
+	lo, hi := c.lengthRange(min, max)
+
+	if lo == hi {
+		return -1, errNoCandidates
+	}
+
+	if c.scorer != nil {
+		return c.pickWeighted(lo, hi)
+	}
+
+	if c.filter != nil {
+		return c.pickFiltered(lo, hi)
+	}
+
+	return c.byLength[lo+c.intn(hi-lo)], nil
+}
+
+// errNoCandidates is returned when no sentence fits the min and max values of a pick. Every way of
+// picking a sentence returns it, so that callers can check for it whichever way they pick.
+var errNoCandidates = errors.New("no candidates with given min and max values")
+
+// pickRange applies the defaults for the min and max values of a pick, and checks that a sentence
+// measured in unit could be found between them. Every way of picking a sentence uses this, so that
+// they all accept the same values.
+func pickRange(min, max int, unit Unit) (int, int, error) {
+	if min < 0 {
+		min = 0
+	}
+
+	if max < 0 {
+		max = math.MaxInt32
+	}
+
+	if min > max {
+		return min, max, errors.New("min value must be smaller than max")
+	}
+
+	if max < unit.Minimum {
+		return min, max, errors.New("max value must be larger than the minimum sentence length")
+	}
+
+	return min, max, nil
+}
+
+// pickFiltered picks a sentence from byLength[lo:hi] that is accepted by the filter of the corpus,
+// using reservoir sampling so that every accepted sentence is equally likely to be chosen.
+func (c *Corpus) pickFiltered(lo, hi int) (int, error) {
+	chosen := -1
+	seen := 0
+
+	for _, i := range c.byLength[lo:hi] {
+		if !c.filter(c.sentences[i]) {
+			continue
+		}
+
+		seen++
+		if c.intn(seen) == 0 {
+			chosen = i
+		}
+	}
+
+	if chosen < 0 {
+		return -1, errNoMatch
+	}
+
+	return chosen, nil
+}
+
+// Generate a random paragraph from the corpus which can consist of one or many random sentences,
+// each picked using `Pick`. A minimum and maximum length for the final paragraph can be provided,
+// measured in the unit of the corpus. Passing a negative value to either one of these will use
+// sensible defaults.
+func (c *Corpus) Generate(min, max int) (string, error) {
+	return generate(c.Pick, c.unit, min, max)
+}
+
+// generate builds a paragraph from sentences chosen by pick until it is longer than min, with
+// each sentence chosen to fit within the space remaining before max.
+func generate(pick func(min, max int) (string, error), unit Unit, min, max int) (out string, err error) {
+	for {
+		sentence, err := pick(-1, max-unit.Length(out))
+
+		if err != nil {
+			return out, err
+		}
+
+		out = out + sentence
+
+		if unit.Length(out) > min {
+			break
+		}
+
+		out = out + " "
+	}
+
+	return out, nil
+}
@@ -0,0 +1,257 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// testSentences are a small corpus of sentences with lengths of 12, 18, 31, 39 and 16 runes.
+var testSentences = []string{
+	"He ran away.",
+	"The van was found.",
+	"Police searched the river bank.",
+	"Fibers were found on the victim's coat.",
+	"Is this the man?",
+}
+
+func TestCorpusPick(t *testing.T) {
+	corpus := NewCorpus(testSentences)
+
+	tests := []struct {
+		min, max int
+		want     []string
+		err      bool
+	}{
+		{-1, -1, testSentences, false},
+		{12, 31, []string{"The van was found.", "Is this the man?"}, false},
+		{11, 13, []string{"He ran away."}, false},
+		{30, -1, []string{"Police searched the river bank.", "Fibers were found on the victim's coat."}, false},
+		{12, 12, nil, true},
+		{39, 100, nil, true},
+		{20, 10, nil, true},
+		{-1, MinimumLineLength - 1, nil, true},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 20; i++ {
+			got, err := corpus.Pick(test.min, test.max)
+
+			if test.err {
+				if err == nil {
+					t.Errorf("Pick(%d, %d) = %q, want an error", test.min, test.max, got)
+				}
+
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("Pick(%d, %d) error = %v", test.min, test.max, err)
+			}
+
+			if !containsString(test.want, got) {
+				t.Errorf("Pick(%d, %d) = %q, want one of %q", test.min, test.max, got, test.want)
+			}
+		}
+	}
+
+	if _, err := NewCorpus(nil).Pick(-1, -1); err == nil {
+		t.Error("Pick() from an empty corpus returned no error")
+	}
+}
+
+func TestCorpusGenerate(t *testing.T) {
+	corpus := NewCorpus(testSentences)
+
+	for i := 0; i < 50; i++ {
+		out, err := corpus.Generate(40, 120)
+		if err != nil {
+			t.Fatalf("Generate(40, 120) error = %v", err)
+		}
+
+		if length := RuneLength(out); length <= 40 || length >= 120 {
+			t.Fatalf("Generate(40, 120) = %q of length %d, want between 40 and 120", out, length)
+		}
+	}
+}
+
+func TestNewCorpusFromSentences(t *testing.T) {
+	corpus := NewCorpusFromSentences([]Sentence{
+		{Text: "Is this the man?", Length: 99, Episode: "s01e01"},
+		{Text: "He ran away", Words: 99},
+	}, WithUnit(Words))
+
+	tests := []struct {
+		sentence    Sentence
+		length      int
+		words       int
+		punctuation string
+		episode     string
+	}{
+		{corpus.Sentence(0), 4, 4, "?", "s01e01"},
+		{corpus.Sentence(1), 3, 3, "", ""},
+	}
+
+	for _, test := range tests {
+		s := test.sentence
+		if s.Length != test.length || s.Words != test.words || s.End != test.punctuation || s.Episode != test.episode {
+			t.Errorf("sentence %q = %d %d %q %q, want %d %d %q %q", s.Text, s.Length, s.Words, s.End, s.Episode,
+				test.length, test.words, test.punctuation, test.episode)
+		}
+	}
+
+	if corpus.Len() != 2 || corpus.Unit().Name != Words.Name {
+		t.Errorf("corpus has %d sentences measured in %s, want 2 in words", corpus.Len(), corpus.Unit().Name)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pickLinear is the original implementation of `Pick`, which filters every sentence on each call.
+// It is kept here to compare against the length index.
+func pickLinear(sentences []string, min, max int) (string, error) {
+	if min < 0 {
+		min = 0
+	}
+
+	if max < 0 {
+		max = math.MaxInt32
+	}
+
+	var filtered []string
+	for _, sentence := range sentences {
+		length := DefaultUnit.Length(sentence)
+		if length > min && length < max {
+			filtered = append(filtered, sentence)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return "", errors.New("no candidates with given min and max values")
+	}
+
+	return filtered[rand.Intn(len(filtered))], nil
+}
+
+func loadBenchmarkSentences(b *testing.B) []string {
+	sentences, err := ReadSentencesFile("sentences.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	texts := make([]string, len(sentences))
+	for i, sentence := range sentences {
+		texts[i] = sentence.Text
+	}
+
+	return texts
+}
+
+func BenchmarkPickLinear(b *testing.B) {
+	sentences := loadBenchmarkSentences(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := pickLinear(sentences, 40, 120); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCorpusPick(b *testing.B) {
+	corpus := NewCorpus(loadBenchmarkSentences(b))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := corpus.Pick(40, 120); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCorpusPickUnbounded(b *testing.B) {
+	corpus := NewCorpus(loadBenchmarkSentences(b))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := corpus.Pick(-1, -1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewCorpus(b *testing.B) {
+	sentences := loadBenchmarkSentences(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		NewCorpus(sentences)
+	}
+}
+
+func TestIndexLengths(t *testing.T) {
+	corpus := NewCorpus([]string{"ccc", "a", "bb", "dd", "", "eee"}, WithUnit(Bytes))
+
+	wantByLength := []int{4, 1, 2, 3, 0, 5}
+	wantLengths := []int{0, 1, 2, 2, 3, 3}
+
+	for n := range wantByLength {
+		if corpus.byLength[n] != wantByLength[n] || corpus.lengths[n] != wantLengths[n] {
+			t.Fatalf("byLength = %v, lengths = %v, want %v and %v", corpus.byLength, corpus.lengths, wantByLength, wantLengths)
+		}
+	}
+
+	tests := []struct {
+		min, max int
+		lo, hi   int
+	}{
+		{-1, 100, 0, 6},
+		{0, 3, 1, 4},
+		{1, 3, 2, 4},
+		{2, 3, 4, 4},
+		{2, 4, 4, 6},
+		{3, 100, 6, 6},
+		{5, 1, 6, 6},
+	}
+
+	for _, test := range tests {
+		if lo, hi := corpus.lengthRange(test.min, test.max); lo != test.lo || hi != test.hi {
+			t.Errorf("lengthRange(%d, %d) = %d, %d, want %d, %d", test.min, test.max, lo, hi, test.lo, test.hi)
+		}
+	}
+}
+
+func TestLengthRangeMatchesLinearScan(t *testing.T) {
+	sentences, err := ReadSentencesFile("sentences.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corpus := NewCorpusFromSentences(sentences)
+
+	for _, bounds := range [][2]int{{40, 120}, {0, 20}, {200, 300}, {100, 101}} {
+		want := 0
+		for _, sentence := range sentences {
+			if length := DefaultUnit.Length(sentence.Text); length > bounds[0] && length < bounds[1] {
+				want++
+			}
+		}
+
+		if lo, hi := corpus.lengthRange(bounds[0], bounds[1]); hi-lo != want {
+			t.Errorf("lengthRange(%d, %d) holds %d sentences, want %d", bounds[0], bounds[1], hi-lo, want)
+		}
+	}
+}
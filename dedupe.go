@@ -0,0 +1,298 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"hash/fnv"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wargarblgarbl/libgosubs/srt"
+)
+
+// SameEpisodeThreshold is the similarity, between 0 and 1, at which two subtitle files are
+// considered to cover the same episode. See `Similarity` for how this is measured.
+var SameEpisodeThreshold = 0.6
+
+// ShingleSize is the number of consecutive words hashed together when fingerprinting the text
+// of a subtitle file. Different sources break cues in different places, so we fingerprint the
+// running text rather than individual cues.
+const ShingleSize = 4
+
+// CueTimingTolerance is how far apart two cues can start and still be considered the same cue
+// when comparing the timing of two subtitle files.
+const CueTimingTolerance = time.Second
+
+// MarkupRegexp matches HTML style markup within a subtitle, such as "<i>" or "<font color="#CCCCC">".
+var MarkupRegexp = regexp.MustCompile(`<\/?.+?>`)
+
+// Source is a fingerprinted subtitle file along with a measure of how good a source of sentences
+// it is. Sources are created with `FingerprintSource`.
+type Source struct {
+	Path    string
+	Quality Quality
+
+	shingles map[uint64]struct{}
+	starts   []time.Duration
+}
+
+// Quality describes the cues found within a subtitle file. The `Score` is used to pick the best
+// source out of a group of subtitle files for the same episode. Higher is better.
+type Quality struct {
+	Cues      int
+	Ignored   int
+	UpperCase int
+	Markup    int
+	Sentences int
+	Score     float64
+}
+
+// SourceGroup is a collection of subtitle files that cover the same episode, ordered from the
+// best source to the worst. Files that `Strip` would skip are always ordered after files it would
+// use.
+type SourceGroup []*Source
+
+// Best returns the highest quality source within the group.
+func (g SourceGroup) Best() *Source {
+	return g[0]
+}
+
+// FingerprintSource parses a subtitle file, fingerprints the normalised cue text and timing, and
+// scores the file on how many usable sentences it contains. A file loses score for cues that would
+// be ignored by `Strip`, for cues in ALL CAPS, and for cues containing markup. Options are used to
+// clean each cue in the same way as `StripSentences`.
+func FingerprintSource(path string, options ...Option) (*Source, error) {
+	config := configure(options)
+
+	target, err := filepath.Abs(path)
+
+	if err != nil {
+		return nil, errors.New("unable to retrieve absolute path for target")
+	}
+
+	subtitles, err := srt.ParseSrt(target)
+
+	if err != nil {
+		return nil, errors.New("error parsing subtitle file")
+	}
+
+	source := &Source{Path: path, shingles: make(map[uint64]struct{})}
+
+	var words []string
+	var lines []cue
+
+	for _, subtitle := range subtitles.Subtitle.Content {
+		raw := strings.Join(subtitle.Line, " ")
+		words = append(words, strings.Fields(normaliseCue(raw))...)
+
+		if start, err := parseTimecode(subtitle.Start); err == nil {
+			source.starts = append(source.starts, start)
+		}
+
+		source.Quality.Cues++
+
+		if MarkupRegexp.MatchString(raw) {
+			source.Quality.Markup++
+		}
+
+		if strings.ToUpper(raw) == raw && strings.ToLower(raw) != raw {
+			source.Quality.UpperCase++
+		}
+
+		line := cleanSubtitle(subtitle, config.normaliser)
+
+		if IgnoreSubtitleRegexp.MatchString(line) {
+			source.Quality.Ignored++
+			continue
+		}
+
+		if line != "" && config.unit.Length(line) > config.unit.Minimum {
+			lines = append(lines, cue{text: line})
+		}
+	}
+
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+ShingleSize], " ")))
+		source.shingles[h.Sum64()] = struct{}{}
+	}
+
+	sort.Slice(source.starts, func(i, j int) bool { return source.starts[i] < source.starts[j] })
+
+	// `Strip` skips the whole file when any cue is ignored, so the file provides no sentences.
+	if source.Quality.Ignored == 0 {
+		source.Quality.Sentences = len(joinSentences(lines))
+	}
+
+	source.Quality.Score = source.Quality.score()
+
+	return source, nil
+}
+
+// score weights the number of sentences by the proportion of cues that are clean. A file that
+// contains any ignored cue is skipped entirely by `Strip`, so it scores nothing.
+func (q Quality) score() float64 {
+	if q.Cues == 0 || q.Ignored > 0 {
+		return 0
+	}
+
+	cues := float64(q.Cues)
+	score := float64(q.Sentences)
+	score *= 1 - 0.5*float64(q.UpperCase)/cues
+	score *= 1 - 0.5*float64(q.Markup)/cues
+
+	return score
+}
+
+// Similarity returns how alike two sources are, between 0 and 1. This is mostly based on how much
+// of the shorter source's text is contained within the other, with a smaller contribution from
+// how many cues start at the same time.
+func Similarity(a, b *Source) float64 {
+	return 0.75*textContainment(a, b) + 0.25*timingOverlap(a, b)
+}
+
+func textContainment(a, b *Source) float64 {
+	small, large := a.shingles, b.shingles
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+
+	if len(small) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for shingle := range small {
+		if _, ok := large[shingle]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(small))
+}
+
+func timingOverlap(a, b *Source) float64 {
+	small, large := a.starts, b.starts
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+
+	if len(small) == 0 {
+		return 0
+	}
+
+	matched := 0
+	j := 0
+	for _, start := range small {
+		for j < len(large) && large[j] < start-CueTimingTolerance {
+			j++
+		}
+
+		if j < len(large) && large[j] <= start+CueTimingTolerance {
+			matched++
+		}
+	}
+
+	return float64(matched) / float64(len(small))
+}
+
+// GroupSources fingerprints each of the subtitle files and groups together those that cover the
+// same episode. Each group is ordered with the best source first, and groups are returned in the
+// order their first file appeared in paths. Files that cannot be parsed are skipped, in the same
+// way as `StripAll`, and options are used to fingerprint each file, see `FingerprintSource`.
+func GroupSources(paths []string, options ...Option) []SourceGroup {
+	var sources []*Source
+
+	for _, path := range paths {
+		source, err := FingerprintSource(path, options...)
+
+		if err != nil {
+			continue
+		}
+
+		sources = append(sources, source)
+	}
+
+	parent := make([]int, len(sources))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range sources {
+		for j := i + 1; j < len(sources); j++ {
+			if Similarity(sources[i], sources[j]) >= SameEpisodeThreshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	var groups []SourceGroup
+	index := make(map[int]int)
+
+	for i, source := range sources {
+		root := find(i)
+		n, ok := index[root]
+
+		if !ok {
+			n = len(groups)
+			index[root] = n
+			groups = append(groups, nil)
+		}
+
+		groups[n] = append(groups[n], source)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			a, b := group[i].Quality, group[j].Quality
+			if (a.Ignored == 0) != (b.Ignored == 0) {
+				return a.Ignored == 0
+			}
+
+			return a.Score > b.Score
+		})
+	}
+
+	return groups
+}
+
+// BestSources returns the path of the best source for every episode found within paths. The result
+// can be passed to `StripAll` to avoid double counting episodes that have more than one subtitle file.
+// Options are used to fingerprint each file, as with `GroupSources`.
+func BestSources(paths []string, options ...Option) (best []string) {
+	for _, group := range GroupSources(paths, options...) {
+		best = append(best, group.Best().Path)
+	}
+
+	return best
+}
+
+// normaliseCue lowercases the text of a cue and strips out markup, punctuation, and speaker changes
+// so that the same cue from two different sources produces the same words.
+func normaliseCue(cue string) string {
+	cue = MarkupRegexp.ReplaceAllString(cue, " ")
+	cue = RemoveFromSubtitleRegexp.ReplaceAllString(cue, " ")
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		if r == '\'' {
+			return -1
+		}
+
+		return ' '
+	}, cue)
+}
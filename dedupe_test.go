@@ -0,0 +1,101 @@
+package forensicfilescorpus
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSubtitles writes each cue to a subtitle file within dir, one second apart, returning the
+// path of the file.
+func writeSubtitles(t *testing.T, dir, name string, cues ...string) string {
+	t.Helper()
+
+	var b strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n00:00:%02d,000 --> 00:00:%02d,500\n%s\n\n", i+1, i, i, cue)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+var episodeCues = []string{
+	"The body was found beside the river on a cold morning.",
+	"Investigators collected fibers from the victim's coat.",
+	"The fibers matched a carpet in the suspect's van.",
+	"He was sentenced to life in prison.",
+}
+
+func TestQualityScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		quality Quality
+		want    float64
+	}{
+		{"no cues", Quality{}, 0},
+		{"clean", Quality{Cues: 10, Sentences: 8}, 8},
+		{"ignored", Quality{Cues: 10, Sentences: 20, Ignored: 1}, 0},
+		{"upper case", Quality{Cues: 10, Sentences: 8, UpperCase: 10}, 4},
+		{"markup", Quality{Cues: 10, Sentences: 8, Markup: 5}, 6},
+	}
+
+	for _, test := range tests {
+		if got := test.quality.score(); got != test.want {
+			t.Errorf("%s: score() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGroupSourcesRanksIgnoredSourcesLast(t *testing.T) {
+	dir := t.TempDir()
+
+	clean := writeSubtitles(t, dir, "clean.srt", episodeCues...)
+
+	// The duplicate has more sentences, but its markup cue makes `Strip` skip the whole file.
+	extra := append([]string{"<i>Forensic Files</i>"}, episodeCues...)
+	extra = append(extra, "Police reopened the case years later.", "The new evidence was conclusive.")
+	ignored := writeSubtitles(t, dir, "ignored.srt", extra...)
+
+	groups := GroupSources([]string{ignored, clean})
+	if len(groups) != 1 {
+		t.Fatalf("GroupSources() returned %d groups, want 1", len(groups))
+	}
+
+	if best := groups[0].Best(); best.Path != clean {
+		t.Errorf("Best() = %s, want %s", best.Path, clean)
+	}
+
+	if q := groups[0][1].Quality; q.Ignored == 0 || q.Sentences != 0 || q.Score != 0 {
+		t.Errorf("ignored source quality = %+v, want ignored cues with no sentences or score", q)
+	}
+
+	if best := BestSources([]string{ignored, clean}); len(best) != 1 || best[0] != clean {
+		t.Errorf("BestSources() = %v, want [%s]", best, clean)
+	}
+
+	if sentences := StripAll(BestSources([]string{ignored, clean})); len(sentences) != len(episodeCues) {
+		t.Errorf("StripAll(BestSources()) returned %d sentences, want %d", len(sentences), len(episodeCues))
+	}
+}
+
+func TestGroupSourcesSeparatesEpisodes(t *testing.T) {
+	dir := t.TempDir()
+
+	a := writeSubtitles(t, dir, "a.srt", episodeCues...)
+	b := writeSubtitles(t, dir, "b.srt",
+		"A jogger discovered the burned car at dawn.",
+		"Arson investigators found traces of gasoline.",
+		"The insurance policy had been changed a week before.",
+	)
+
+	if groups := GroupSources([]string{a, b}); len(groups) != 2 {
+		t.Errorf("GroupSources() returned %d groups, want 2", len(groups))
+	}
+}
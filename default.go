@@ -0,0 +1,25 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"io"
+)
+
+// defaultCorpus is the compiled corpus used when no path is given, see `RegisterDefault`.
+var defaultCorpus io.ReaderAt
+
+// RegisterDefault sets the compiled corpus used by the file loading functions, such as
+// `PickFromFile` and `LoadCorpus`, when they are given an empty path. The defaultcorpus package
+// embeds a corpus into the binary, and registers it when its `Register` function is called:
+//
+//	defaultcorpus.Register()
+func RegisterDefault(r io.ReaderAt) {
+	defaultCorpus = r
+}
+
+// HasDefault reports whether a default corpus has been registered.
+func HasDefault() bool {
+	return defaultCorpus != nil
+}
+
+var errNoDefault = errors.New("no corpus path given and no default corpus registered")
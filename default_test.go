@@ -0,0 +1,67 @@
+package forensicfilescorpus
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDefaultCorpus(t *testing.T) {
+	defer RegisterDefault(nil)
+
+	RegisterDefault(nil)
+
+	if HasDefault() {
+		t.Errorf("HasDefault() = true before RegisterDefault")
+	}
+
+	if IsCompiled("") {
+		t.Errorf("IsCompiled(\"\") = true before RegisterDefault")
+	}
+
+	if _, err := LoadCorpus(""); err != errNoDefault {
+		t.Errorf("LoadCorpus(\"\") error = %v, want %v", err, errNoDefault)
+	}
+
+	if _, err := OpenCompiled(""); err != errNoDefault {
+		t.Errorf("OpenCompiled(\"\") error = %v, want %v", err, errNoDefault)
+	}
+
+	RegisterDefault(bytes.NewReader(compileTestCorpus(t)))
+
+	if !HasDefault() {
+		t.Errorf("HasDefault() = false after RegisterDefault")
+	}
+
+	if !IsCompiled("") {
+		t.Errorf("IsCompiled(\"\") = false after RegisterDefault")
+	}
+
+	corpus, err := LoadCorpus("")
+	if err != nil {
+		t.Fatalf("LoadCorpus(\"\") error = %v", err)
+	}
+
+	if got := corpus.Len(); got != len(testSentences) {
+		t.Errorf("LoadCorpus(\"\").Len() = %d, want %d", got, len(testSentences))
+	}
+
+	compiled, err := OpenCompiled("")
+	if err != nil {
+		t.Fatalf("OpenCompiled(\"\") error = %v", err)
+	}
+
+	defer compiled.Close()
+
+	if got := compiled.Len(); got != len(testSentences) {
+		t.Errorf("OpenCompiled(\"\").Len() = %d, want %d", got, len(testSentences))
+	}
+
+	sentence, err := PickFromFile("", 10, 20)
+	if err != nil {
+		t.Fatalf("PickFromFile(\"\") error = %v", err)
+	}
+
+	if !containsString(testSentences, sentence) {
+		t.Errorf("PickFromFile(\"\") = %q, not a test sentence", sentence)
+	}
+}
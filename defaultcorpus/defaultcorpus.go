@@ -0,0 +1,31 @@
+// Package defaultcorpus embeds a compiled corpus of Forensic Files sentences into the binary, so
+// that picking does not depend on a sentences file being found next to it. Calling `Register`
+// makes the corpus the default, which is used whenever a file loading function is given an empty
+// path.
+//
+// The embedded corpus is generated from sentences.txt at the root of the repository. Run
+// `go generate ./defaultcorpus` or `make corpus` after changing sentences.txt, and commit the
+// regenerated sentences.ffc along with it.
+package defaultcorpus
+
+//go:generate go run gen.go ../sentences.txt sentences.ffc
+
+import (
+	"bytes"
+	_ "embed"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+//go:embed sentences.ffc
+var compiled []byte
+
+// Register sets the embedded corpus as the default, see `forensicfilescorpus.RegisterDefault`.
+func Register() {
+	forensicfilescorpus.RegisterDefault(bytes.NewReader(compiled))
+}
+
+// Open returns the embedded corpus. Options are used for the source of randomisation.
+func Open(options ...forensicfilescorpus.Option) (*forensicfilescorpus.CompiledCorpus, error) {
+	return forensicfilescorpus.NewCompiledCorpus(bytes.NewReader(compiled), options...)
+}
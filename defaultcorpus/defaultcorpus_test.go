@@ -0,0 +1,64 @@
+package defaultcorpus
+
+import (
+	"bytes"
+	"testing"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func TestOpen(t *testing.T) {
+	corpus, err := Open()
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if corpus.Len() == 0 {
+		t.Fatalf("Open().Len() = 0, want the embedded sentences")
+	}
+
+	if _, err := corpus.Pick(20, 80); err != nil {
+		t.Errorf("Open().Pick(20, 80) error = %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer forensicfilescorpus.RegisterDefault(nil)
+
+	if forensicfilescorpus.HasDefault() {
+		t.Fatalf("HasDefault() = true before Register, importing the package must not register it")
+	}
+
+	Register()
+
+	if !forensicfilescorpus.HasDefault() {
+		t.Fatalf("HasDefault() = false after Register")
+	}
+
+	corpus, err := forensicfilescorpus.LoadCorpus("")
+	if err != nil {
+		t.Fatalf("LoadCorpus(\"\") error = %v", err)
+	}
+
+	embedded, _ := Open()
+	if corpus.Len() != embedded.Len() {
+		t.Errorf("LoadCorpus(\"\").Len() = %d, want %d", corpus.Len(), embedded.Len())
+	}
+}
+
+// TestEmbeddedUpToDate checks the committed corpus was generated from the current sentences.txt.
+func TestEmbeddedUpToDate(t *testing.T) {
+	corpus, err := forensicfilescorpus.LoadCorpus("../sentences.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want bytes.Buffer
+	if err := forensicfilescorpus.WriteCompiled(&want, corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(compiled, want.Bytes()) {
+		t.Errorf("sentences.ffc does not match ../sentences.txt, run `go generate ./defaultcorpus`")
+	}
+}
@@ -0,0 +1,25 @@
+//go:build ignore
+
+// gen.go compiles the sentences file given as its first argument into the compiled corpus at its
+// second argument. It is run by `go generate` and is not part of the package, so that the corpus
+// can be generated before the package it is embedded into can be built.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	forensicfilescorpus "github.com/karlbright/forensic-files-corpus"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Println("USAGE: go run gen.go sentences.txt sentences.ffc")
+		os.Exit(2)
+	}
+
+	if err := forensicfilescorpus.CompileFile(os.Args[1], os.Args[2]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,196 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"strings"
+)
+
+// ExactTries is the number of paragraphs `Corpus.GenerateExact` builds looking for one whose
+// measured length fits, for units where the length of a paragraph is not quite the sum of its
+// sentences and separators.
+var ExactTries = 10
+
+// lengthGroup is every sentence of one length that can be used by `Corpus.GenerateExact`, and the
+// length each of them adds to a paragraph including the separator before it.
+type lengthGroup struct {
+	weight    int
+	sentences []int
+}
+
+// GenerateExact generates a random paragraph that is longer than min and shorter than max, as with
+// `Generate`. Rather than adding random sentences until the paragraph passes min, which often fails
+// to find a last sentence short enough to fit when min and max are close, this searches for a set
+// of sentences whose combined length, including the spaces between them, lands within the range.
+//
+// Lengths that can be made from the sentences of the corpus are found up front, so a range that no
+// set of sentences fits is reported straight away. A reachable length is then chosen at random, and
+// a random set of sentences making that length is chosen and shuffled. A filter given to the corpus
+// with `WithFilter` applies to every sentence, and no sentence is used twice.
+func (c *Corpus) GenerateExact(min, max int) (string, error) {
+	if len(c.sentences) == 0 {
+		return "", errors.New("unable to generate from empty sentences slice")
+	}
+
+	min, max, err := pickRange(min, max, c.unit)
+	if err != nil {
+		return "", err
+	}
+
+	// A paragraph of n sentences has n-1 separators, so every sentence is given the length of one
+	// separator and the target is moved up by one separator to match.
+	separator := c.unit.Length(" ")
+	groups, longest, sum := c.lengthGroups(separator, max)
+
+	if len(groups) == 0 {
+		return "", errors.New("no candidates shorter than max")
+	}
+
+	lo := min + 1 + separator
+	hi := max - 1 + separator
+
+	// Every sentence together is the longest paragraph that can be made, which is checked before
+	// the reachable totals are worked out, as they take space for every total up to hi.
+	if sum < lo {
+		return "", errors.New("no combination of sentences fits within given min and max values")
+	}
+
+	// Any set of sentences reaching lo can stop as soon as it does, so no set needs to be longer
+	// than lo plus the longest sentence, nor longer than every sentence together.
+	if hi > lo+longest-1 {
+		hi = lo + longest - 1
+	}
+
+	if hi > sum {
+		hi = sum
+	}
+
+	reach := reachable(groups, hi)
+
+	var targets []int
+	for total := lo; total <= hi; total++ {
+		if reach[len(groups)][total] {
+			targets = append(targets, total)
+		}
+	}
+
+	if len(targets) == 0 {
+		return "", errors.New("no combination of sentences fits within given min and max values")
+	}
+
+	for try := 0; try < ExactTries; try++ {
+		out := c.exactParagraph(groups, reach, targets[c.intn(len(targets))])
+
+		if length := c.unit.Length(out); length > min && length < max {
+			return out, nil
+		}
+	}
+
+	return "", errors.New("unable to generate a paragraph measuring within given min and max values")
+}
+
+// lengthGroups groups the sentences accepted by the filter of the corpus by length, leaving out
+// sentences that are too long to fit within max. It also returns the longest weight of any group,
+// and the sum of the weights of every sentence.
+func (c *Corpus) lengthGroups(separator, max int) (groups []lengthGroup, longest, sum int) {
+	for n, i := range c.byLength {
+		if c.lengths[n] >= max {
+			break
+		}
+
+		if c.filter != nil && !c.filter(c.sentences[i]) {
+			continue
+		}
+
+		weight := c.lengths[n] + separator
+		if weight <= 0 {
+			continue
+		}
+
+		if len(groups) == 0 || groups[len(groups)-1].weight != weight {
+			groups = append(groups, lengthGroup{weight: weight})
+		}
+
+		groups[len(groups)-1].sentences = append(groups[len(groups)-1].sentences, i)
+		longest = weight
+		sum += weight
+	}
+
+	return groups, longest, sum
+}
+
+// reachable returns, for each number of groups k, which totals up to limit can be made from the
+// first k groups, using each sentence at most once.
+func reachable(groups []lengthGroup, limit int) [][]bool {
+	reach := make([][]bool, len(groups)+1)
+	reach[0] = make([]bool, limit+1)
+	reach[0][0] = true
+
+	// used holds how many sentences of the current group make up each total, so that a group is not
+	// used more times than it has sentences.
+	used := make([]int, limit+1)
+
+	for k, group := range groups {
+		previous := reach[k]
+		current := make([]bool, limit+1)
+
+		for total := 0; total <= limit; total++ {
+			if previous[total] {
+				current[total] = true
+				used[total] = 0
+				continue
+			}
+
+			// Otherwise the total is one more sentence of this group on top of a smaller total,
+			// as long as that total leaves a sentence of the group unused.
+			from := total - group.weight
+			if from >= 0 && current[from] && used[from] < len(group.sentences) {
+				current[total] = true
+				used[total] = used[from] + 1
+			}
+		}
+
+		reach[k+1] = current
+	}
+
+	return reach
+}
+
+// exactParagraph chooses a random set of sentences making total, working back through the groups
+// and choosing at random how many sentences of each group to use out of the counts that leave a
+// total the earlier groups can make.
+func (c *Corpus) exactParagraph(groups []lengthGroup, reach [][]bool, total int) string {
+	var chosen []int
+
+	for k := len(groups); k > 0; k-- {
+		group := groups[k-1]
+
+		var counts []int
+		for count := 0; count <= len(group.sentences) && count*group.weight <= total; count++ {
+			if reach[k-1][total-count*group.weight] {
+				counts = append(counts, count)
+			}
+		}
+
+		count := counts[c.intn(len(counts))]
+		total -= count * group.weight
+
+		sentences := append([]int{}, group.sentences...)
+		for i := 0; i < count; i++ {
+			j := i + c.intn(len(sentences)-i)
+			sentences[i], sentences[j] = sentences[j], sentences[i]
+			chosen = append(chosen, sentences[i])
+		}
+	}
+
+	for i := len(chosen) - 1; i > 0; i-- {
+		j := c.intn(i + 1)
+		chosen[i], chosen[j] = chosen[j], chosen[i]
+	}
+
+	texts := make([]string, len(chosen))
+	for i, sentence := range chosen {
+		texts[i] = c.sentences[sentence].Text
+	}
+
+	return strings.Join(texts, " ")
+}
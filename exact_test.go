@@ -0,0 +1,188 @@
+package forensicfilescorpus
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// reachableTotals returns every total that can be made using up to the number of sentences of each
+// group, by trying every count of every group.
+func reachableTotals(groups []lengthGroup) map[int]bool {
+	totals := map[int]bool{0: true}
+
+	for _, group := range groups {
+		next := map[int]bool{}
+		for total := range totals {
+			for count := 0; count <= len(group.sentences); count++ {
+				next[total+count*group.weight] = true
+			}
+		}
+
+		totals = next
+	}
+
+	return totals
+}
+
+func TestReachable(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts map[int]int
+	}{
+		{"single sentence", map[int]int{5: 1}},
+		{"one group", map[int]int{3: 4}},
+		{"limited counts", map[int]int{2: 1, 3: 2, 7: 1}},
+		{"repeated weights beat by limits", map[int]int{4: 2, 6: 1, 10: 3}},
+		{"coprime weights", map[int]int{5: 3, 7: 2, 11: 1, 13: 2}},
+	}
+
+	for _, test := range tests {
+		var groups []lengthGroup
+		limit, next := 0, 0
+
+		for weight := 1; weight <= 20; weight++ {
+			count, ok := test.counts[weight]
+			if !ok {
+				continue
+			}
+
+			group := lengthGroup{weight: weight}
+			for i := 0; i < count; i++ {
+				group.sentences = append(group.sentences, next)
+				next++
+			}
+
+			groups = append(groups, group)
+			limit += weight * count
+		}
+
+		want := reachableTotals(groups)
+		reach := reachable(groups, limit+5)
+
+		for total := 0; total <= limit+5; total++ {
+			if got := reach[len(groups)][total]; got != want[total] {
+				t.Errorf("%s: reachable()[%d] = %v, want %v", test.name, total, got, want[total])
+			}
+		}
+
+		// Each row only uses the groups before it.
+		for k := range groups {
+			want := reachableTotals(groups[:k])
+			for total := 0; total <= limit+5; total++ {
+				if got := reach[k][total]; got != want[total] {
+					t.Errorf("%s: reachable()[%d][%d] = %v, want %v", test.name, k, total, got, want[total])
+				}
+			}
+		}
+	}
+}
+
+// exactSentences has sentences of many lengths, several sharing a length.
+var exactSentences = []string{
+	"He ran away.",
+	"She ran off.",
+	"It was dark.",
+	"The van was found.",
+	"The car was found.",
+	"Is this the man?",
+	"Police searched the river bank.",
+	"Fibers were found on the victim's coat.",
+	"Nobody heard a thing.",
+	"The dog barked all night long.",
+	"Why did he lie?",
+	"Blood was found on the kitchen floor.",
+}
+
+func TestGenerateExact(t *testing.T) {
+	tests := []struct {
+		min, max int
+	}{
+		{-1, -1},
+		{30, 34},
+		{60, 62},
+		{100, 110},
+		{150, 160},
+		{11, 13},
+	}
+
+	for _, test := range tests {
+		for seed := int64(0); seed < 20; seed++ {
+			corpus := NewCorpus(exactSentences, WithSource(rand.NewSource(seed)))
+
+			out, err := corpus.GenerateExact(test.min, test.max)
+			if err != nil {
+				t.Errorf("GenerateExact(%d, %d) error = %v", test.min, test.max, err)
+				continue
+			}
+
+			length := DefaultUnit.Length(out)
+			if length <= test.min || test.max >= 0 && length >= test.max {
+				t.Errorf("GenerateExact(%d, %d) = %q with length %d", test.min, test.max, out, length)
+			}
+
+			// Every sentence of the corpus is found within the paragraph at most once.
+			rest := out
+			for _, sentence := range exactSentences {
+				if strings.Count(out, sentence) > 1 {
+					t.Errorf("GenerateExact(%d, %d) = %q, uses %q twice", test.min, test.max, out, sentence)
+				}
+
+				rest = strings.Replace(rest, sentence, "", 1)
+			}
+
+			if strings.TrimSpace(rest) != "" {
+				t.Errorf("GenerateExact(%d, %d) = %q, left %q that is not a sentence", test.min, test.max, out, rest)
+			}
+		}
+	}
+}
+
+func TestGenerateExactFilter(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		corpus := NewCorpus(exactSentences, WithSource(rand.NewSource(seed)), WithFilter(EndsWith("?")))
+
+		out, err := corpus.GenerateExact(20, 40)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if out != "Is this the man? Why did he lie?" && out != "Why did he lie? Is this the man?" {
+			t.Errorf("GenerateExact(20, 40) with a filter = %q, want both questions", out)
+		}
+	}
+}
+
+func TestGenerateExactInvalid(t *testing.T) {
+	every := strings.Join(exactSentences, " ")
+
+	tests := []struct {
+		name      string
+		sentences []string
+		min, max  int
+		err       string
+	}{
+		{"empty corpus", nil, -1, -1, "unable to generate from empty sentences slice"},
+		{"min larger than max", exactSentences, 50, 40, "min value must be smaller than max"},
+		{"max below the minimum", exactSentences, -1, MinimumLineLength - 1, "max value must be larger than the minimum sentence length"},
+		{"max below every sentence", exactSentences, -1, 12, "no candidates shorter than max"},
+		{"range between lengths", []string{"He ran away.", "The van was found."}, 12, 18, "no combination of sentences fits within given min and max values"},
+		{"longer than every sentence", exactSentences, len(every), -1, "no combination of sentences fits within given min and max values"},
+		{"far longer than every sentence", exactSentences, 1 << 30, -1, "no combination of sentences fits within given min and max values"},
+	}
+
+	for _, test := range tests {
+		corpus := NewCorpus(test.sentences, WithSource(rand.NewSource(1)))
+
+		out, err := corpus.GenerateExact(test.min, test.max)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%s: GenerateExact(%d, %d) = %q, %v, want error %q", test.name, test.min, test.max, out, err, test.err)
+		}
+	}
+
+	// Every sentence together is the longest paragraph that can be made.
+	out, err := NewCorpus(exactSentences).GenerateExact(len(every)-1, -1)
+	if err != nil || len(out) != len(every) {
+		t.Errorf("GenerateExact(%d, -1) = %q, %v, want every sentence", len(every)-1, out, err)
+	}
+}
@@ -0,0 +1,168 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math"
+	"regexp"
+)
+
+// Filter reports whether a sentence should be considered when picking. Filters can be combined
+// with `All`, `Any` and `Not`, and given to a corpus with `WithFilter`.
+type Filter func(Sentence) bool
+
+// WithFilter restricts picks from the corpus to sentences accepted by every one of filters, as well
+// as the min and max lengths of each pick. This applies to `Generate` too, as it picks each of its
+// sentences. Picks return an error when no sentence within the length range is accepted.
+func WithFilter(filters ...Filter) Option {
+	return func(c *Corpus) {
+		combined := append([]Filter{c.filter}, filters...)
+		c.filter = All(combined...)
+	}
+}
+
+// errNoMatch is returned when sentences fit the length range of a pick but none of them are
+// accepted by the filter given with `WithFilter`.
+var errNoMatch = errors.New("no sentences within the length range match the given filters")
+
+// Between returns a filter accepting sentences longer than min and shorter than max, using the same
+// rules as `Pick`. Passing a negative value to either one of these removes that bound.
+func Between(min, max int) Filter {
+	if min < 0 {
+		min = 0
+	}
+
+	if max < 0 {
+		max = math.MaxInt32
+	}
+
+	return func(sentence Sentence) bool {
+		return sentence.Length > min && sentence.Length < max
+	}
+}
+
+// All returns a filter accepting sentences that are accepted by every one of filters. A nil filter
+// accepts every sentence.
+func All(filters ...Filter) Filter {
+	return func(sentence Sentence) bool {
+		for _, filter := range filters {
+			if filter != nil && !filter(sentence) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns a filter accepting sentences that are accepted by at least one of filters.
+func Any(filters ...Filter) Filter {
+	return func(sentence Sentence) bool {
+		for _, filter := range filters {
+			if filter == nil || filter(sentence) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a filter accepting sentences that filter does not accept.
+func Not(filter Filter) Filter {
+	return func(sentence Sentence) bool {
+		return !filter(sentence)
+	}
+}
+
+// Contains returns a filter accepting sentences that contain every one of words, ignoring case. A
+// word only matches whole words within the sentence, so "DNA" does not match "DNAs". Passing more
+// than one word within a string, such as "crime scene", matches them as a phrase.
+func Contains(words ...string) Filter {
+	phrases := tokenisePhrases(words)
+
+	return func(sentence Sentence) bool {
+		tokens := Tokenise(sentence.Text)
+
+		for _, phrase := range phrases {
+			if !containsPhrase(tokens, phrase) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Excludes returns a filter accepting sentences that contain none of words, matched in the same way
+// as `Contains`.
+func Excludes(words ...string) Filter {
+	phrases := tokenisePhrases(words)
+
+	return func(sentence Sentence) bool {
+		tokens := Tokenise(sentence.Text)
+
+		for _, phrase := range phrases {
+			if containsPhrase(tokens, phrase) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Matches returns a filter accepting sentences matched by re.
+func Matches(re *regexp.Regexp) Filter {
+	return func(sentence Sentence) bool {
+		return re.MatchString(sentence.Text)
+	}
+}
+
+// EndsWith returns a filter accepting sentences that end with any of punctuation, such as "?" for
+// questions. An empty string accepts sentences that do not end with punctuation. See `EndToken`.
+func EndsWith(punctuation ...string) Filter {
+	return func(sentence Sentence) bool {
+		for _, p := range punctuation {
+			if sentence.End == p {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func tokenisePhrases(words []string) [][]string {
+	var phrases [][]string
+
+	for _, word := range words {
+		var phrase []string
+		for _, token := range Tokenise(word) {
+			phrase = append(phrase, token.Text)
+		}
+
+		if len(phrase) > 0 {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	return phrases
+}
+
+func containsPhrase(tokens []Token, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		matched := true
+		for j, word := range phrase {
+			if tokens[i+j].Text != word {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package forensicfilescorpus
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFilters(t *testing.T) {
+	question := Sentence{Text: "Was the DNA found on the crime scene tape?", End: "?", Length: 42}
+	statement := Sentence{Text: "DNAs were found. Blood was not.", End: ".", Length: 31}
+	unfinished := Sentence{Text: "The crime and the scene", End: "", Length: 23}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		sentence Sentence
+		want     bool
+	}{
+		{"contains word", Contains("dna"), question, true},
+		{"contains whole words only", Contains("DNA"), statement, false},
+		{"contains every word", Contains("dna", "tape"), question, true},
+		{"contains missing word", Contains("dna", "blood"), question, false},
+		{"contains phrase", Contains("crime scene"), question, true},
+		{"contains phrase in order", Contains("crime scene"), unfinished, false},
+		{"contains nothing", Contains(), question, true},
+		{"excludes word", Excludes("blood"), statement, false},
+		{"excludes absent word", Excludes("blood"), question, true},
+		{"excludes any word", Excludes("van", "tape"), question, false},
+		{"matches", Matches(regexp.MustCompile(`^DNAs? `)), statement, true},
+		{"does not match", Matches(regexp.MustCompile(`^DNAs? `)), question, false},
+		{"ends with", EndsWith("?"), question, true},
+		{"ends with any", EndsWith("!", "."), statement, true},
+		{"ends without punctuation", EndsWith(""), unfinished, true},
+		{"does not end with", EndsWith("?"), statement, false},
+		{"between", Between(30, 40), statement, true},
+		{"between excludes min", Between(31, 40), statement, false},
+		{"between excludes max", Between(-1, 42), question, false},
+		{"between without bounds", Between(-1, -1), question, true},
+		{"all", All(Contains("dna"), EndsWith("?")), question, true},
+		{"all rejects", All(Contains("dna"), EndsWith(".")), question, false},
+		{"all of nothing", All(), question, true},
+		{"all skips nil", All(nil, EndsWith("?")), question, true},
+		{"any", Any(EndsWith("."), Contains("tape")), question, true},
+		{"any rejects", Any(EndsWith("."), Contains("blood")), question, false},
+		{"any of nothing", Any(), question, false},
+		{"not", Not(EndsWith("?")), question, false},
+	}
+
+	for _, test := range tests {
+		if got := test.filter(test.sentence); got != test.want {
+			t.Errorf("%s: filter(%q) = %v, want %v", test.name, test.sentence.Text, got, test.want)
+		}
+	}
+}
+
+func TestWithFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		min     int
+		max     int
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "contains",
+			options: []Option{WithFilter(Contains("found"))},
+			min:     -1,
+			max:     -1,
+			want:    []string{"The van was found.", "Fibers were found on the victim's coat."},
+		},
+		{
+			name:    "filters combine across options",
+			options: []Option{WithFilter(Contains("found")), WithFilter(Excludes("van"))},
+			min:     -1,
+			max:     -1,
+			want:    []string{"Fibers were found on the victim's coat."},
+		},
+		{
+			name:    "questions",
+			options: []Option{WithFilter(EndsWith("?"))},
+			min:     -1,
+			max:     -1,
+			want:    []string{"Is this the man?"},
+		},
+		{
+			name:    "no match within length",
+			options: []Option{WithFilter(EndsWith("?"))},
+			min:     20,
+			max:     -1,
+			wantErr: errNoMatch,
+		},
+	}
+
+	for _, test := range tests {
+		corpus := NewCorpus(testSentences, test.options...)
+
+		for i := 0; i < 20; i++ {
+			got, err := corpus.Pick(test.min, test.max)
+			if err != test.wantErr {
+				t.Errorf("%s: Pick() error = %v, want %v", test.name, err, test.wantErr)
+				break
+			}
+
+			if err == nil && !containsString(test.want, got) {
+				t.Errorf("%s: Pick() = %q, want one of %q", test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestWithFilterDoesNotModifyArguments(t *testing.T) {
+	filters := make([]Filter, 1, 2)
+	filters[0] = Contains("found")
+
+	NewCorpus(testSentences, WithFilter(EndsWith("?")), WithFilter(filters...))
+
+	if extra := filters[:2][1]; extra != nil {
+		t.Errorf("WithFilter() wrote into the spare capacity of its arguments")
+	}
+}
+
+func TestGenerateWithFilter(t *testing.T) {
+	corpus := NewCorpus(testSentences, WithFilter(Contains("found")))
+	found := []string{"The van was found.", "Fibers were found on the victim's coat."}
+
+	for i := 0; i < 20; i++ {
+		paragraph, err := corpus.Generate(-1, 100)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		if !containsString(found, paragraph) {
+			t.Errorf("Generate() = %q, want only sentences containing %q", paragraph, "found")
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// HistoryWindow is how long a posted sentence is kept from being posted again, or having a
+	// similar sentence posted, see `NotRecentlyPosted`.
+	HistoryWindow = 30 * 24 * time.Hour
+
+	// HistorySimilarityThreshold is how alike a sentence can be to a recently posted sentence before
+	// it is rejected, as given by `SentenceSimilarity`.
+	HistorySimilarityThreshold = 0.6
+)
+
+// HistoryEntry is a sentence that has been posted, when it was posted and where it can be found.
+type HistoryEntry struct {
+	ID     string    `json:"id"`
+	Text   string    `json:"text"`
+	Posted time.Time `json:"posted"`
+	URL    string    `json:"url,omitempty"`
+}
+
+// NewHistoryEntry creates an entry for text posted at url, identified by `SentenceID`.
+func NewHistoryEntry(text, url string, posted time.Time) HistoryEntry {
+	return HistoryEntry{SentenceID(text), text, posted, url}
+}
+
+// HistoryStore records the sentences that have been posted.
+type HistoryStore interface {
+	// Add records a posted sentence.
+	Add(entry HistoryEntry) error
+
+	// Entries returns every recorded sentence, oldest first.
+	Entries() ([]HistoryEntry, error)
+}
+
+// HistoryStoreFor returns the store for a location, chosen by the scheme of the location in the
+// same way as `BagStoreFor`. A local path ending in ".db" or ".bolt" is an embedded key-value
+// database, see `BoltHistoryStore`, and any other path is JSON Lines, see `FileHistoryStore`.
+func HistoryStoreFor(location string) (HistoryStore, error) {
+	if !strings.Contains(location, "://") {
+		return localHistoryStore(location), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return localHistoryStore(u.Path), nil
+	case "s3":
+		return S3HistoryStore{s3SourceFor(u)}, nil
+	}
+
+	return nil, errors.New("unsupported history location, must be a path or an s3:// URL")
+}
+
+func localHistoryStore(path string) HistoryStore {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".bolt":
+		return BoltHistoryStore(path)
+	}
+
+	return FileHistoryStore(path)
+}
+
+// FileHistoryStore records posted sentences as JSON Lines within the file at the given path. Each
+// entry is appended to the file, so earlier entries are never rewritten.
+type FileHistoryStore string
+
+// Add appends entry to the file, creating the file if needed.
+func (s FileHistoryStore) Add(entry HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	dest, err := os.OpenFile(string(s), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dest.Write(append(line, '\n')); err != nil {
+		dest.Close()
+		return err
+	}
+
+	return dest.Close()
+}
+
+// Entries reads every entry from the file, or returns no entries if the file does not exist.
+func (s FileHistoryStore) Entries() ([]HistoryEntry, error) {
+	src, err := os.Open(string(s))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	return readHistory(src)
+}
+
+// S3HistoryStore records posted sentences as JSON Lines within an object of an S3 compatible object
+// store. Objects cannot be appended to, so every entry is read and written again on each `Add`.
+type S3HistoryStore struct {
+	Object *S3Source
+}
+
+// Add appends entry to the object, creating the object if needed.
+func (s S3HistoryStore) Add(entry HistoryEntry) error {
+	data, err := s.Object.get()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.Object.put(append(append(data, line...), '\n'))
+}
+
+// Entries reads every entry from the object, or returns no entries if the object does not exist.
+func (s S3HistoryStore) Entries() ([]HistoryEntry, error) {
+	data, err := s.Object.get()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return readHistory(bytes.NewReader(data))
+}
+
+func readHistory(r io.Reader) (entries []HistoryEntry, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// NotRecentlyPosted returns a filter rejecting sentences that were posted after since, along with
+// sentences whose `SentenceSimilarity` to any of those posts is at least
+// `HistorySimilarityThreshold`. Use `time.Now().Add(-HistoryWindow)` for since to keep to the
+// default window.
+func NotRecentlyPosted(entries []HistoryEntry, since time.Time) Filter {
+	posted := map[string]bool{}
+	var recent []map[string]bool
+
+	for _, entry := range entries {
+		if entry.Posted.Before(since) {
+			continue
+		}
+
+		posted[entry.ID] = true
+		recent = append(recent, wordSet(entry.Text))
+	}
+
+	return func(sentence Sentence) bool {
+		if posted[SentenceID(sentence.Text)] {
+			return false
+		}
+
+		words := wordSet(sentence.Text)
+		for _, other := range recent {
+			if jaccard(words, other) >= HistorySimilarityThreshold {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// SentenceSimilarity returns how alike two sentences are, between 0 and 1, as the proportion of
+// their distinct words they share, leaving out `Stopwords`.
+func SentenceSimilarity(a, b string) float64 {
+	return jaccard(wordSet(a), wordSet(b))
+}
+
+func wordSet(text string) map[string]bool {
+	words := map[string]bool{}
+
+	for _, token := range Tokenise(text) {
+		if !Stopwords[token.Text] {
+			words[token.Text] = true
+		}
+	}
+
+	return words
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
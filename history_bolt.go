@@ -0,0 +1,92 @@
+package forensicfilescorpus
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// historyBucket is the bucket within a `BoltHistoryStore` database holding the posted sentences.
+var historyBucket = []byte("history")
+
+// BoltHistoryStore records posted sentences within an embedded Bolt key-value database at the given
+// path, for when the history grows too large to read as JSON Lines on every post. Entries are keyed
+// by the time they were posted followed by their `SentenceID`, so they are kept oldest first.
+//
+// The database is opened for each call and locked while open, so only one process can use the
+// store at a time.
+type BoltHistoryStore string
+
+// Add records entry within the database, creating the database if needed.
+func (s BoltHistoryStore) Add(entry HistoryEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	db, err := bolt.Open(string(s), 0644, &bolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return err
+	}
+
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(historyBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(historyKey(entry), value)
+	})
+}
+
+// Entries reads every entry from the database, or returns no entries if the database does not
+// exist.
+func (s BoltHistoryStore) Entries() ([]HistoryEntry, error) {
+	if _, err := os.Stat(string(s)); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	db, err := bolt.Open(string(s), 0644, &bolt.Options{Timeout: 10 * time.Second, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	defer db.Close()
+
+	var entries []HistoryEntry
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historyBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, value []byte) error {
+			var entry HistoryEntry
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+
+	return entries, err
+}
+
+// historyKey orders entries by when they were posted. Times before 1970 are kept as the earliest.
+func historyKey(entry HistoryEntry) []byte {
+	nanos := entry.Posted.UnixNano()
+	if nanos < 0 {
+		nanos = 0
+	}
+
+	key := make([]byte, 8, 8+len(entry.ID))
+	binary.BigEndian.PutUint64(key, uint64(nanos))
+	return append(key, entry.ID...)
+}
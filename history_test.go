@@ -0,0 +1,187 @@
+package forensicfilescorpus
+
+import (
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNotRecentlyPosted(t *testing.T) {
+	now := time.Date(2019, 1, 31, 12, 0, 0, 0, time.UTC)
+	entries := []HistoryEntry{
+		NewHistoryEntry("Fibers were found on the victim's coat.", "", now.Add(-40*24*time.Hour)),
+		NewHistoryEntry("The van was found.", "", now.Add(-2*24*time.Hour)),
+		NewHistoryEntry("Police searched the river bank at dawn.", "", now.Add(-time.Hour)),
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"never posted", "Is this the man?", true},
+		{"posted within the window", "The van was found.", false},
+		{"posted before the window", "Fibers were found on the victim's coat.", true},
+		{"similar to a recent post", "Police searched the river bank.", false},
+		{"similar ignoring stopwords", "The van was found!", false},
+		{"sharing a few words", "The river was searched by divers.", true},
+		{"similar to a post before the window", "Fibers were found on his coat.", true},
+	}
+
+	filter := NotRecentlyPosted(entries, now.Add(-HistoryWindow))
+
+	for _, test := range tests {
+		if got := filter(Sentence{Text: test.text}); got != test.want {
+			t.Errorf("%s: NotRecentlyPosted()(%q) = %v, want %v", test.name, test.text, got, test.want)
+		}
+	}
+
+	if filter := NotRecentlyPosted(entries, now.Add(time.Minute)); !filter(Sentence{Text: "The van was found."}) {
+		t.Errorf("NotRecentlyPosted() with since after every post rejected a posted sentence")
+	}
+}
+
+func TestSentenceSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"The van was found.", "The van was found.", 1},
+		{"The van was found.", "the VAN was found!", 1},
+		{"The van was found.", "The car was found.", 1.0 / 3},
+		{"The van was found.", "Is this the man?", 0},
+		{"The van was found.", "", 0},
+		{"It was the one.", "It was the one.", 0},
+	}
+
+	for _, test := range tests {
+		if got := SentenceSimilarity(test.a, test.b); got != test.want {
+			t.Errorf("SentenceSimilarity(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestHistoryStores(t *testing.T) {
+	dir := t.TempDir()
+	posted := time.Date(2019, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	entries := []HistoryEntry{
+		NewHistoryEntry("He ran away.", "https://twitter.com/skippalenik/status/1", posted),
+		NewHistoryEntry("Is this the man?", "https://twitter.com/skippalenik/status/2", posted.Add(time.Hour)),
+		NewHistoryEntry("He ran away.", "https://twitter.com/skippalenik/status/3", posted.Add(48*time.Hour)),
+	}
+
+	tests := []struct {
+		name  string
+		store HistoryStore
+	}{
+		{"json lines", FileHistoryStore(filepath.Join(dir, "history.jsonl"))},
+		{"bolt", BoltHistoryStore(filepath.Join(dir, "history.db"))},
+	}
+
+	for _, test := range tests {
+		empty, err := test.store.Entries()
+		if err != nil || len(empty) != 0 {
+			t.Errorf("%s: Entries() before any Add = %v, %v, want none", test.name, empty, err)
+		}
+
+		for _, entry := range entries {
+			if err := test.store.Add(entry); err != nil {
+				t.Fatalf("%s: Add() error = %v", test.name, err)
+			}
+		}
+
+		got, err := test.store.Entries()
+		if err != nil {
+			t.Fatalf("%s: Entries() error = %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, entries) {
+			t.Errorf("%s: Entries() = %+v, want %+v", test.name, got, entries)
+		}
+	}
+}
+
+func TestBoltHistoryStoreOrder(t *testing.T) {
+	store := BoltHistoryStore(filepath.Join(t.TempDir(), "history.bolt"))
+	posted := time.Date(2019, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	// Entries are kept oldest first, whatever order they are added in.
+	later := NewHistoryEntry("Is this the man?", "", posted.Add(time.Hour))
+	earlier := NewHistoryEntry("He ran away.", "", posted)
+
+	for _, entry := range []HistoryEntry{later, earlier} {
+		if err := store.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []HistoryEntry{earlier, later}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHistoryStoreFor(t *testing.T) {
+	tests := []struct {
+		location string
+		want     HistoryStore
+		wantErr  bool
+	}{
+		{location: "history.jsonl", want: FileHistoryStore("history.jsonl")},
+		{location: "history.db", want: BoltHistoryStore("history.db")},
+		{location: "/tmp/history.BOLT", want: BoltHistoryStore("/tmp/history.BOLT")},
+		{location: "file:///tmp/history.db", want: BoltHistoryStore("/tmp/history.db")},
+		{location: "file:///tmp/history.jsonl", want: FileHistoryStore("/tmp/history.jsonl")},
+		{location: "https://example.com/history.jsonl", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := HistoryStoreFor(test.location)
+		if (err != nil) != test.wantErr {
+			t.Errorf("HistoryStoreFor(%q) error = %v, want error %v", test.location, err, test.wantErr)
+			continue
+		}
+
+		if !test.wantErr && got != test.want {
+			t.Errorf("HistoryStoreFor(%q) = %#v, want %#v", test.location, got, test.want)
+		}
+	}
+}
+
+func TestShuffleBagKeepsRecentlyPostedSentences(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		corpus := NewCorpus(testSentences, WithSource(rand.NewSource(seed)))
+		store := &memoryBagStore{}
+		now := time.Date(2019, 1, 31, 12, 0, 0, 0, time.UTC)
+
+		// The sentence posted just before the bag was started is rejected for the first picks, as
+		// the history filter does when a sentence was posted by hand.
+		var history []HistoryEntry
+		history = append(history, NewHistoryEntry("Is this the man?", "", now))
+
+		counts := map[string]int{}
+		for i := 0; i < len(testSentences); i++ {
+			now = now.Add(24 * time.Hour)
+			filter := NotRecentlyPosted(history, now.Add(-2*24*time.Hour))
+
+			sentence := nextCommitted(t, NewShuffleBag(corpus, store), filter)
+			history = append(history, NewHistoryEntry(sentence.Text, "", now))
+			counts[sentence.Text]++
+		}
+
+		// Every sentence is used once within the round, including the one that was passed over
+		// while it was recent.
+		for _, text := range testSentences {
+			if counts[text] != 1 {
+				t.Errorf("seed %d: %q was used %d times in a round, want once", seed, text, counts[text])
+			}
+		}
+	}
+}
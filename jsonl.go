@@ -0,0 +1,153 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// IsJSONLines reports whether path has a JSON Lines extension, either ".jsonl" or ".ndjson". This
+// ignores any compression extension, so "sentences.jsonl.gz" is also JSON Lines.
+func IsJSONLines(path string) bool {
+	switch strings.ToLower(filepath.Ext(trimCompressionExt(path))) {
+	case ".jsonl", ".ndjson":
+		return true
+	}
+
+	return false
+}
+
+// WriteJSONLines writes each sentence to w as a JSON object on its own line.
+func WriteJSONLines(w io.Writer, sentences []Sentence) error {
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	for _, sentence := range sentences {
+		if err := encoder.Encode(sentence); err != nil {
+			return err
+		}
+	}
+
+	return out.Flush()
+}
+
+// ReadSentences reads every sentence from r. The format is detected from the first byte: JSON Lines
+// when it is "{", otherwise plain text with one sentence per line and no metadata.
+func ReadSentences(r io.Reader) ([]Sentence, error) {
+	return readAll(newSentenceScanner(r, formatUnknown))
+}
+
+// ReadSentencesFile reads every sentence from the file provided by path parameter. The format is
+// taken from the extension when it is ".jsonl", ".ndjson" or ".txt", and otherwise detected in the
+// same way as `ReadSentences`. Compressed files are decompressed, see `OpenFile`.
+func ReadSentencesFile(path string) ([]Sentence, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	return readAll(newSentenceScanner(src, formatOf(path)))
+}
+
+func readAll(scanner *sentenceScanner) (sentences []Sentence, err error) {
+	for scanner.Scan() {
+		sentences = append(sentences, scanner.Sentence())
+	}
+
+	return sentences, scanner.Err()
+}
+
+type format int
+
+const (
+	formatUnknown format = iota
+	formatText
+	formatJSONLines
+)
+
+func formatOf(path string) format {
+	path = trimCompressionExt(path)
+
+	if IsJSONLines(path) {
+		return formatJSONLines
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".txt" {
+		return formatText
+	}
+
+	return formatUnknown
+}
+
+// sentenceScanner reads sentences one line at a time from either plain text or JSON Lines.
+type sentenceScanner struct {
+	lines    *bufio.Scanner
+	format   format
+	sentence Sentence
+	err      error
+}
+
+func newSentenceScanner(r io.Reader, f format) *sentenceScanner {
+	reader := bufio.NewReader(r)
+
+	if f == formatUnknown {
+		f = formatText
+
+		if first, err := reader.Peek(1); err == nil && first[0] == '{' {
+			f = formatJSONLines
+		}
+	}
+
+	lines := bufio.NewScanner(reader)
+	lines.Buffer(nil, 1024*1024)
+
+	return &sentenceScanner{lines: lines, format: f}
+}
+
+// Scan advances to the next sentence, returning false when there are no more sentences or an
+// error occurred.
+func (s *sentenceScanner) Scan() bool {
+	for s.err == nil && s.lines.Scan() {
+		line := s.lines.Bytes()
+
+		if s.format == formatText {
+			s.sentence = Sentence{Text: string(line)}
+			return true
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		s.sentence = Sentence{}
+		s.err = json.Unmarshal(line, &s.sentence)
+		return s.err == nil
+	}
+
+	return false
+}
+
+// Sentence returns the most recent sentence read by Scan.
+func (s *sentenceScanner) Sentence() Sentence {
+	return s.sentence
+}
+
+// Err returns the first error that occurred while scanning.
+func (s *sentenceScanner) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.lines.Err()
+}
@@ -0,0 +1,274 @@
+package forensicfilescorpus
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsJSONLines(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"sentences.jsonl", true},
+		{"sentences.ndjson", true},
+		{"SENTENCES.JSONL", true},
+		{"sentences.jsonl.gz", true},
+		{"sentences.txt", false},
+		{"sentences.txt.gz", false},
+		{"sentences", false},
+	}
+
+	for _, test := range tests {
+		if got := IsJSONLines(test.path); got != test.want {
+			t.Errorf("IsJSONLines(%q) = %v, want %v", test.path, got, test.want)
+		}
+	}
+}
+
+func TestReadSentences(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Sentence
+	}{
+		{
+			name:  "plain text",
+			input: "He ran away.\nIs this the man?\n",
+			want:  []Sentence{{Text: "He ran away."}, {Text: "Is this the man?"}},
+		},
+		{
+			name:  "plain text keeps braces inside a line",
+			input: "He ran away.\n{not json}\n",
+			want:  []Sentence{{Text: "He ran away."}, {Text: "{not json}"}},
+		},
+		{
+			name:  "json lines",
+			input: `{"text":"He ran away.","episode":"s01e01","index":3,"speaker":"Skip Palenik","start":"00:01:02,345","stop":"00:01:04,000"}` + "\n",
+			want: []Sentence{{
+				Text:    "He ran away.",
+				Episode: "s01e01",
+				Index:   3,
+				Speaker: "Skip Palenik",
+				Start:   Timecode(time.Minute + 2345*time.Millisecond),
+				Stop:    Timecode(time.Minute + 4*time.Second),
+			}},
+		},
+		{
+			name:  "json lines skips blank lines",
+			input: "{\"text\":\"He ran away.\"}\n\n{\"text\":\"Is this the man?\"}\n",
+			want:  []Sentence{{Text: "He ran away."}, {Text: "Is this the man?"}},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ReadSentences(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("%s: ReadSentences() error = %v", test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ReadSentences() = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReadSentencesInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"malformed object", "{\"text\":\"He ran away.\"\n"},
+		{"invalid timecode", "{\"text\":\"He ran away.\",\"start\":\"soon\"}\n"},
+		{"plain text after json", "{\"text\":\"He ran away.\"}\nIs this the man?\n"},
+	}
+
+	for _, test := range tests {
+		if _, err := ReadSentences(strings.NewReader(test.input)); err == nil {
+			t.Errorf("%s: ReadSentences() error = nil, want an error", test.name)
+		}
+	}
+}
+
+func TestWriteJSONLinesRoundTrip(t *testing.T) {
+	sentences := []Sentence{
+		{
+			Text:    "Fibers were found on the victim's <coat>.",
+			Episode: "s02e05",
+			Index:   7,
+			Speaker: "Skip Palenik",
+			Start:   Timecode(12*time.Second + 500*time.Millisecond),
+			Stop:    Timecode(15 * time.Second),
+			Tags:    []string{"evidence"},
+		},
+		{Text: "Is this the man?"},
+	}
+
+	var b bytes.Buffer
+	if err := WriteJSONLines(&b, sentences); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(b.String(), `\u003c`) {
+		t.Errorf("WriteJSONLines() escaped HTML: %s", b.String())
+	}
+
+	if lines := strings.Count(b.String(), "\n"); lines != len(sentences) {
+		t.Errorf("WriteJSONLines() wrote %d lines, want %d", lines, len(sentences))
+	}
+
+	got, err := ReadSentences(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, sentences) {
+		t.Errorf("ReadSentences(WriteJSONLines()) = %+v, want %+v", got, sentences)
+	}
+}
+
+func TestReadSentencesFileFormat(t *testing.T) {
+	dir := t.TempDir()
+	line := `{"text":"He ran away."}`
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		// The extension wins over the first byte, so a ".txt" file is always plain text.
+		{"sentences.txt", line},
+		{"sentences.jsonl", "He ran away."},
+		{"sentences.ndjson", "He ran away."},
+		{"sentences", "He ran away."},
+	}
+
+	for _, test := range tests {
+		path := writeSentences(t, dir, test.name, []string{line})
+
+		got, err := ReadSentencesFile(path)
+		if err != nil {
+			t.Errorf("%s: ReadSentencesFile() error = %v", test.name, err)
+			continue
+		}
+
+		if len(got) != 1 || got[0].Text != test.want {
+			t.Errorf("%s: ReadSentencesFile() = %+v, want text %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestStripAllToFileJSONLines(t *testing.T) {
+	dir := t.TempDir()
+	subtitles := writeSubtitles(t, dir, "episode.srt", episodeCues...)
+	output := filepath.Join(dir, "sentences.jsonl")
+
+	if err := StripAllToFile([]string{subtitles}, output); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadSentencesFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(episodeCues) {
+		t.Fatalf("ReadSentencesFile() = %d sentences, want %d", len(got), len(episodeCues))
+	}
+
+	for i, sentence := range got {
+		if sentence.Text != episodeCues[i] {
+			t.Errorf("sentence %d: Text = %q, want %q", i, sentence.Text, episodeCues[i])
+		}
+
+		start := Timecode(time.Duration(i) * time.Second)
+		if sentence.Start != start || sentence.Stop != start+Timecode(500*time.Millisecond) {
+			t.Errorf("sentence %d: Start, Stop = %v, %v, want %v, %v", i, sentence.Start, sentence.Stop,
+				start, start+Timecode(500*time.Millisecond))
+		}
+	}
+}
+
+func TestStripSentencesSpeaker(t *testing.T) {
+	path := writeSubtitles(t, t.TempDir(), "episode.srt",
+		"SKIP PALENIK: The fibers came from a carpet.",
+		"The carpet was made in a single mill in Georgia.",
+		"NARRATOR: Detectives searched the van that night.",
+		"DETECTIVE: We found the carpet under the seats.",
+		"The van had been cleaned with bleach.",
+	)
+
+	sentences, err := StripSentences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"SKIP PALENIK", "", "NARRATOR", "DETECTIVE", ""}
+	if len(sentences) != len(want) {
+		t.Fatalf("StripSentences() = %d sentences, want %d", len(sentences), len(want))
+	}
+
+	for i, sentence := range sentences {
+		if sentence.Speaker != want[i] {
+			t.Errorf("sentence %d (%q): Speaker = %q, want %q", i, sentence.Text, sentence.Speaker, want[i])
+		}
+	}
+}
+
+func TestTimecode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Timecode
+	}{
+		{"00:00:00,000", 0},
+		{"00:01:02,345", Timecode(time.Minute + 2345*time.Millisecond)},
+		{"01:00:00,001", Timecode(time.Hour + time.Millisecond)},
+		{" 00:00:59,999 ", Timecode(59999 * time.Millisecond)},
+		{"00:00:01,001", Timecode(time.Second + time.Millisecond)},
+		{"00:00:00,003", Timecode(3 * time.Millisecond)},
+		{"00:00:04,005", Timecode(4*time.Second + 5*time.Millisecond)},
+		{"12:34:56,789", Timecode(12*time.Hour + 34*time.Minute + 56789*time.Millisecond)},
+	}
+
+	for _, test := range tests {
+		got, err := parseTimecode(test.input)
+		if err != nil {
+			t.Errorf("parseTimecode(%q) error = %v", test.input, err)
+			continue
+		}
+
+		if Timecode(got) != test.want {
+			t.Errorf("parseTimecode(%q) = %v, want %v", test.input, Timecode(got), test.want)
+		}
+
+		if s := strings.TrimSpace(test.input); Timecode(got).String() != s {
+			t.Errorf("Timecode(%q).String() = %q, want %q", test.input, Timecode(got).String(), s)
+		}
+	}
+
+	// Every millisecond of a second parses exactly and is written back unchanged.
+	for ms := 0; ms < 1000; ms++ {
+		want := fmt.Sprintf("00:00:01,%03d", ms)
+		got, err := parseTimecode(want)
+		if err != nil || Timecode(got).String() != want {
+			t.Errorf("parseTimecode(%q) = %v, %v, want %s", want, Timecode(got), err, want)
+		}
+	}
+
+	invalid := []string{"", "00:01", "aa:00:00,000", "00:bb:00,000", "00:00:cc", "00:00:01,0x1", "00:00:01,1234567890"}
+	for _, input := range invalid {
+		if _, err := parseTimecode(input); err == nil {
+			t.Errorf("parseTimecode(%q) error = nil, want an error", input)
+		}
+	}
+}
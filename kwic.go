@@ -0,0 +1,202 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KWICLine is a single occurrence of a term within a corpus, shown keyword in context: the text of
+// the sentence to the left of the term, the term as it appears, and the text to the right.
+type KWICLine struct {
+	Sentence Sentence
+	Index    int
+	Left     string
+	Match    string
+	Right    string
+}
+
+// KWICOrder is the order of the lines within a concordance, see `SortKWIC`.
+type KWICOrder int
+
+const (
+	// KWICCorpusOrder keeps the lines in the order they appear within the corpus.
+	KWICCorpusOrder KWICOrder = iota
+
+	// KWICLeftOrder orders the lines by the words to the left of the term, nearest word first.
+	KWICLeftOrder
+
+	// KWICRightOrder orders the lines by the words to the right of the term.
+	KWICRightOrder
+)
+
+// KWICGroup is the lines of a concordance found within a single episode.
+type KWICGroup struct {
+	Episode string
+	Lines   []KWICLine
+}
+
+// KWICFromFile is a convenience method to list every occurrence of term within the corpus within
+// the file provided by path parameter. Options are used to load the corpus, see `LoadCorpus`.
+func KWICFromFile(path, term string, width int, options ...Option) ([]KWICLine, error) {
+	corpus, err := LoadCorpus(path, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return corpus.KWIC(term, width), nil
+}
+
+// KWIC lists every occurrence of term within the corpus, in the order they appear, with up to width
+// characters of the sentence either side. The term is matched ignoring case and only as whole
+// words, and may be a phrase such as "crime scene", in the same way as `Contains`.
+func (c *Corpus) KWIC(term string, width int) []KWICLine {
+	var lines []KWICLine
+
+	phrases := tokenisePhrases([]string{term})
+	if len(phrases) == 0 {
+		return lines
+	}
+
+	phrase := phrases[0]
+
+	for i, sentence := range c.sentences {
+		tokens := Tokenise(sentence.Text)
+
+		for n := 0; n+len(phrase) <= len(tokens); n++ {
+			if !containsPhrase(tokens[n:n+len(phrase)], phrase) {
+				continue
+			}
+
+			start, end := tokens[n].Start, tokens[n+len(phrase)-1].End
+
+			lines = append(lines, KWICLine{
+				Sentence: sentence,
+				Index:    i,
+				Left:     lastRunes(sentence.Text[:start], width),
+				Match:    sentence.Text[start:end],
+				Right:    firstRunes(sentence.Text[end:], width),
+			})
+		}
+	}
+
+	return lines
+}
+
+func lastRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		runes = runes[len(runes)-n:]
+	}
+
+	return string(runes)
+}
+
+func firstRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+
+	return string(runes)
+}
+
+// SortKWIC orders lines in place. Lines with the same context keep the order they had.
+func SortKWIC(lines []KWICLine, order KWICOrder) {
+	switch order {
+	case KWICLeftOrder:
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lessWords(reversedWords(lines[i].Left), reversedWords(lines[j].Left))
+		})
+	case KWICRightOrder:
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lessWords(contextWords(lines[i].Right), contextWords(lines[j].Right))
+		})
+	default:
+		sort.SliceStable(lines, func(i, j int) bool {
+			return lines[i].Index < lines[j].Index
+		})
+	}
+}
+
+func contextWords(s string) []string {
+	var words []string
+	for _, token := range Tokenise(s) {
+		words = append(words, token.Text)
+	}
+
+	return words
+}
+
+func reversedWords(s string) []string {
+	words := contextWords(s)
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+
+	return words
+}
+
+func lessWords(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// GroupKWIC groups lines by the episode they were found in, ordered by episode and keeping the
+// order of the lines within each episode. Lines from a corpus without episode metadata are all
+// within a single group with an empty episode.
+func GroupKWIC(lines []KWICLine) []KWICGroup {
+	var groups []KWICGroup
+	index := map[string]int{}
+
+	for _, line := range lines {
+		i, ok := index[line.Sentence.Episode]
+		if !ok {
+			i = len(groups)
+			index[line.Sentence.Episode] = i
+			groups = append(groups, KWICGroup{Episode: line.Sentence.Episode})
+		}
+
+		groups[i].Lines = append(groups[i].Lines, line)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Episode < groups[j].Episode
+	})
+
+	return groups
+}
+
+// WriteKWIC writes lines to w as tab separated values with a header row, with the columns episode,
+// index within the corpus, left, match and right. Tabs within the text are replaced with spaces.
+func WriteKWIC(w io.Writer, lines []KWICLine) error {
+	out := bufio.NewWriter(w)
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+	out.WriteString("episode\tindex\tleft\tmatch\tright\n")
+
+	for _, line := range lines {
+		fields := []string{
+			line.Sentence.Episode,
+			strconv.Itoa(line.Index),
+			line.Left,
+			line.Match,
+			line.Right,
+		}
+
+		for i, field := range fields {
+			fields[i] = clean.Replace(field)
+		}
+
+		out.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+
+	return out.Flush()
+}
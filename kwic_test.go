@@ -0,0 +1,176 @@
+package forensicfilescorpus
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var kwicSentences = []Sentence{
+	{Text: "The fibers matched a carpet in the van.", Episode: "s01e02"},
+	{Text: "Luminol revealed blood on the carpet.", Episode: "s01e01"},
+	{Text: "Carpet fibers, carpet fibers everywhere.", Episode: "s01e02"},
+	{Text: "The carpets were new.", Episode: "s01e01"},
+}
+
+type kwicContext struct {
+	Index int
+	Left  string
+	Match string
+	Right string
+}
+
+func kwicContexts(lines []KWICLine) []kwicContext {
+	var contexts []kwicContext
+	for _, line := range lines {
+		contexts = append(contexts, kwicContext{line.Index, line.Left, line.Match, line.Right})
+	}
+
+	return contexts
+}
+
+func TestKWIC(t *testing.T) {
+	tests := []struct {
+		term  string
+		width int
+		want  []kwicContext
+	}{
+		{"carpet", 100, []kwicContext{
+			{0, "The fibers matched a ", "carpet", " in the van."},
+			{1, "Luminol revealed blood on the ", "carpet", "."},
+			{2, "", "Carpet", " fibers, carpet fibers everywhere."},
+			{2, "Carpet fibers, ", "carpet", " fibers everywhere."},
+		}},
+		{"carpet", 5, []kwicContext{
+			{0, "ed a ", "carpet", " in t"},
+			{1, " the ", "carpet", "."},
+			{2, "", "Carpet", " fibe"},
+			{2, "ers, ", "carpet", " fibe"},
+		}},
+		{"carpet fibers", 0, []kwicContext{
+			{2, "", "Carpet fibers", ""},
+			{2, "", "carpet fibers", ""},
+		}},
+		{"the van", 3, []kwicContext{{0, "in ", "the van", "."}}},
+		{"DNA", 10, nil},
+		{"...", 10, nil},
+	}
+
+	corpus := NewCorpusFromSentences(kwicSentences)
+
+	for _, test := range tests {
+		got := kwicContexts(corpus.KWIC(test.term, test.width))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("KWIC(%q, %d) = %+v, want %+v", test.term, test.width, got, test.want)
+		}
+	}
+}
+
+func TestKWICWidthCountsRunes(t *testing.T) {
+	corpus := NewCorpus([]string{"Caf\u00e9 fibers matched the caf\u00e9 carpet."})
+
+	lines := corpus.KWIC("fibers", 3)
+	if len(lines) != 1 || lines[0].Left != "f\u00e9 " || lines[0].Right != " ma" {
+		t.Errorf("KWIC(\"fibers\", 3) = %+v, want left %q and right %q", kwicContexts(lines), "f\u00e9 ", " ma")
+	}
+}
+
+func TestSortKWIC(t *testing.T) {
+	lines := []KWICLine{
+		{Index: 0, Left: "the blue", Right: "was found"},
+		{Index: 1, Left: "a red", Right: "in the van"},
+		{Index: 2, Left: "the red", Right: "was found in"},
+		{Index: 3, Left: "", Right: ""},
+	}
+
+	tests := []struct {
+		order KWICOrder
+		want  []int
+	}{
+		{KWICCorpusOrder, []int{0, 1, 2, 3}},
+		// The left context is compared from the nearest word outwards, so "blue" is before "red" and
+		// the two "red" lines are ordered by "a" and "the".
+		{KWICLeftOrder, []int{3, 0, 1, 2}},
+		{KWICRightOrder, []int{3, 1, 0, 2}},
+	}
+
+	for _, test := range tests {
+		sorted := append([]KWICLine(nil), lines...)
+		SortKWIC(sorted, test.order)
+
+		var got []int
+		for _, line := range sorted {
+			got = append(got, line.Index)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SortKWIC(%d) = %v, want %v", test.order, got, test.want)
+		}
+	}
+
+	// Sorting back into corpus order undoes any other order.
+	SortKWIC(lines, KWICRightOrder)
+	SortKWIC(lines, KWICCorpusOrder)
+
+	for i, line := range lines {
+		if line.Index != i {
+			t.Errorf("SortKWIC(KWICCorpusOrder) line %d has index %d", i, line.Index)
+		}
+	}
+}
+
+func TestGroupKWIC(t *testing.T) {
+	lines := NewCorpusFromSentences(kwicSentences).KWIC("carpet", 10)
+	groups := GroupKWIC(lines)
+
+	if len(groups) != 2 || groups[0].Episode != "s01e01" || groups[1].Episode != "s01e02" {
+		t.Fatalf("GroupKWIC() = %+v, want episodes s01e01 and s01e02", groups)
+	}
+
+	tests := []struct {
+		group int
+		want  []int
+	}{
+		{0, []int{1}},
+		{1, []int{0, 2, 2}},
+	}
+
+	for _, test := range tests {
+		var got []int
+		for _, line := range groups[test.group].Lines {
+			got = append(got, line.Index)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("GroupKWIC() group %d = %v, want %v", test.group, got, test.want)
+		}
+	}
+
+	plain := GroupKWIC(NewCorpus(testSentences).KWIC("found", 10))
+	if len(plain) != 1 || plain[0].Episode != "" || len(plain[0].Lines) != 2 {
+		t.Errorf("GroupKWIC() without episodes = %+v, want one group of 2 lines", plain)
+	}
+
+	if got := GroupKWIC(nil); len(got) != 0 {
+		t.Errorf("GroupKWIC(nil) = %+v, want no groups", got)
+	}
+}
+
+func TestWriteKWIC(t *testing.T) {
+	lines := []KWICLine{
+		{Sentence: Sentence{Episode: "s01e01"}, Index: 4, Left: "on the\t", Match: "carpet", Right: ".\r\n"},
+		{Index: 9, Left: "", Match: "Carpet", Right: " fibers"},
+	}
+
+	var b bytes.Buffer
+	if err := WriteKWIC(&b, lines); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "episode\tindex\tleft\tmatch\tright\n" +
+		"s01e01\t4\ton the \tcarpet\t.  \n" +
+		"\t9\t\tCarpet\t fibers\n"
+	if b.String() != want {
+		t.Errorf("WriteKWIC() = %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,72 @@
+package forensicfilescorpus
+
+import "testing"
+
+func TestUnitLengths(t *testing.T) {
+	tests := []struct {
+		in                             string
+		bytes, runes, graphemes, words int
+	}{
+		{"", 0, 0, 0, 0},
+		{"It was murder.", 14, 14, 14, 3},
+		{"José said “no”", 19, 14, 14, 3},
+		{"Jose\u0301", 6, 5, 4, 1},
+		{"  two   words ", 14, 14, 14, 2},
+		{"\U0001F469\u200d\U0001F52C lab", 15, 7, 5, 2},
+	}
+
+	for _, test := range tests {
+		if got := ByteLength(test.in); got != test.bytes {
+			t.Errorf("ByteLength(%q) = %d, want %d", test.in, got, test.bytes)
+		}
+
+		if got := RuneLength(test.in); got != test.runes {
+			t.Errorf("RuneLength(%q) = %d, want %d", test.in, got, test.runes)
+		}
+
+		if got := GraphemeLength(test.in); got != test.graphemes {
+			t.Errorf("GraphemeLength(%q) = %d, want %d", test.in, got, test.graphemes)
+		}
+
+		if got := WordCount(test.in); got != test.words {
+			t.Errorf("WordCount(%q) = %d, want %d", test.in, got, test.words)
+		}
+	}
+}
+
+func TestUnitByName(t *testing.T) {
+	for _, unit := range Units {
+		got, err := UnitByName(unit.Name)
+		if err != nil || got.Name != unit.Name {
+			t.Errorf("UnitByName(%q) = %q, %v", unit.Name, got.Name, err)
+		}
+	}
+
+	if _, err := UnitByName("furlongs"); err == nil {
+		t.Error("UnitByName(\"furlongs\") returned no error")
+	}
+}
+
+func TestStripWithUnit(t *testing.T) {
+	path := writeSubtitles(t, t.TempDir(), "unit.srt", "Stop it.", "He ran.", "The van was found abandoned.")
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    int
+	}{
+		{"default", nil, 1},
+		{"words", []Option{WithUnit(Words)}, 3},
+	}
+
+	for _, test := range tests {
+		sentences, err := Strip(path, test.options...)
+		if err != nil {
+			t.Fatalf("%s: Strip() error = %v", test.name, err)
+		}
+
+		if len(sentences) != test.want {
+			t.Errorf("%s: Strip() = %q, want %d sentences", test.name, sentences, test.want)
+		}
+	}
+}
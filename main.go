@@ -3,9 +3,7 @@ package forensicfilescorpus
 import (
 	"bufio"
 	"errors"
-	"math"
-	"math/rand"
-	"os"
+	"io"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -35,44 +33,87 @@ var IgnoreSubtitleRegexp = regexp.MustCompile(`^(>> Narrator:|Narrator:|^[^a-z]+
 // StartToken matches against lines that can be used to begin a sentence. We do this by checking for
 // a capital letter, or a number. We also ensure that the line does not end with a quotation mark
 // to avoid an edge case where some titles and dialogue was being seen as a sentence in itself.
-var StartToken = regexp.MustCompile(`^[A-Z0-9].+[^"]$`)
+// Both straight and curly closing quotation marks are considered.
+var StartToken = regexp.MustCompile(`^[A-Z0-9].+[^"”]$`)
 
 // EndToken matches against lines that we feel comfortable in ending a sentence in. These are the
 // common characters that will end a sentence.
 // - question mark
 // - excalamation mark
 // - a full stop
-// - a double quotation mark.
+// - an ellipsis, written as "…"
+// - a double quotation mark, either straight or curly.
 // Although we do not accept this within `StartToken` at the end of a line, we allow it here as
 // we can be confident that a subtitle will not split a quote from someone over a line with the
 // double quotation mark being by itself at the end of a previous line. We hope.
-var EndToken = regexp.MustCompile(`(\?|!|\.|")$`)
+var EndToken = regexp.MustCompile(`(\?|!|\.|…|"|”)$`)
+
+// SpeakerRegexp matches the name of the person speaking at the start of a subtitle, such as
+// "DIANNE M. ANDERSON:" on a line by itself, or "Skip Palenik: I'm a genius!". These are removed
+// from the subtitle by `RemoveFromSubtitleRegexp` but kept as metadata for the sentence.
+var SpeakerRegexp = regexp.MustCompile(`^(?:>> )?([^:\[\]-][^:]*):(?: |$)`)
 
 // StripAllToFile is a convinence method to strip all relelvant usbtitles and save them out
-// to a given path, with each sentence being seperated by a line break.
-func StripAllToFile(paths []string, output string) error {
-	dest, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY, 0644)
+// to a given path, with each sentence being seperated by a line break. When the output path has
+// a JSON Lines extension, such as "sentences.jsonl", each line is a JSON object holding the
+// sentence along with its metadata. See `Sentence`. When the output path has a compression
+// extension, such as "sentences.txt.gz", the file is compressed. Options are used to strip each
+// file, see `StripSentences`.
+func StripAllToFile(paths []string, output string, options ...Option) error {
+	return StripAllToCompressedFile(paths, output, CompressionOf(output), options...)
+}
+
+// StripAllToCompressedFile strips all relevant subtitles and saves them out in the same way as
+// `StripAllToFile`, compressing the file with the given compression.
+func StripAllToCompressedFile(paths []string, output string, compression Compression, options ...Option) error {
+	dest, err := CreateFile(output, compression)
+	if err != nil {
+		return err
+	}
+
+	sentences := StripAllSentences(paths, options...)
+
+	if IsJSONLines(output) {
+		err = WriteJSONLines(dest, sentences)
+	} else {
+		err = writeText(dest, sentences)
+	}
+
 	if err != nil {
+		dest.Close()
 		return err
 	}
 
-	defer dest.Close()
+	return dest.Close()
+}
 
-	sentences := StripAll(paths)
+// writeText writes the text of each sentence on its own line.
+func writeText(w io.Writer, sentences []Sentence) error {
+	out := bufio.NewWriter(w)
 
 	for _, sentence := range sentences {
-		dest.WriteString(sentence)
-		dest.WriteString("\n")
+		out.WriteString(sentence.Text)
+		out.WriteString("\n")
 	}
 
-	return nil
+	return out.Flush()
 }
 
 // StripAll is a convenience method to strip relevant subtitle sentences from a number of
 // subtitle files. See `Strip` for more information on how the subtitles are stripped.
-func StripAll(paths []string) (all []string) {
+func StripAll(paths []string, options ...Option) (all []string) {
+	for _, sentence := range StripAllSentences(paths, options...) {
+		all = append(all, sentence.Text)
+	}
+
+	return all
+}
+
+// StripAllSentences strips relevant subtitle sentences from a number of subtitle files, keeping
+// the metadata for each sentence. See `StripSentences`.
+func StripAllSentences(paths []string, options ...Option) (all []Sentence) {
 	for _, path := range paths {
-		sentences, err := Strip(path)
+		sentences, err := StripSentences(path, options...)
 
 		if err != nil {
 			continue
@@ -89,7 +130,24 @@ func StripAll(paths []string) (all []string) {
 // stripping does not contain any ignored subtitles. See `IgnoreSubtitleRegexp` for more information
 // on what can cause a subtitle file to be ignored. In the case of a subtitle file encountering
 // a subtitle that matches the ignoring rules, then the whole subtitle is ignored.
-func Strip(path string) (sentences []string, err error) {
+func Strip(path string, options ...Option) (sentences []string, err error) {
+	stripped, err := StripSentences(path, options...)
+
+	for _, sentence := range stripped {
+		sentences = append(sentences, sentence.Text)
+	}
+
+	return sentences, err
+}
+
+// StripSentences strips a subtitle file in the same way as `Strip`, keeping the metadata for each
+// sentence: the episode, taken from the name of the subtitle file, the position of the sentence
+// within the episode, when the sentence is shown, and who is speaking. Subtitles are cleaned with
+// the normaliser given by `WithNormaliser`, or `DefaultNormaliser` when none is given, and lines
+// no longer than the `Minimum` of the unit given by `WithUnit` are left out.
+func StripSentences(path string, options ...Option) (sentences []Sentence, err error) {
+	config := configure(options)
+
 	target, err := filepath.Abs(path)
 
 	if err != nil {
@@ -102,28 +160,68 @@ func Strip(path string) (sentences []string, err error) {
 		return sentences, errors.New("error parsing subtitle file")
 	}
 
-	var lines []string
+	var lines []cue
 
 	for _, subtitle := range subtitles.Subtitle.Content {
-		subtitle := strings.Join(subtitle.Line, " ")
-		subtitle = RemoveFromSubtitleRegexp.ReplaceAllString(subtitle, "")
+		// A speaker label only applies to its own cue, so a cue without one is narration or an
+		// unnamed speaker and must not inherit the name from an earlier cue.
+		var speaker string
+		if match := SpeakerRegexp.FindStringSubmatch(strings.Join(subtitle.Line, " ")); match != nil {
+			speaker = strings.TrimSpace(match[1])
+		}
 
-		if IgnoreSubtitleRegexp.MatchString(subtitle) {
+		line := cleanSubtitle(subtitle, config.normaliser)
+
+		if IgnoreSubtitleRegexp.MatchString(line) {
 			return sentences, errors.New("ignored subtitle file")
 		}
 
-		if subtitle != "" && len(subtitle) > MinimumLineLength {
-			lines = append(lines, subtitle)
+		if line != "" && config.unit.Length(line) > config.unit.Minimum {
+			start, _ := parseTimecode(subtitle.Start)
+			end, _ := parseTimecode(subtitle.End)
+			lines = append(lines, cue{line, speaker, Timecode(start), Timecode(end)})
 		}
 	}
 
+	episode := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	sentences = joinSentences(lines)
+
+	for i := range sentences {
+		sentences[i].Episode = episode
+		sentences[i].Index = i
+	}
+
+	return sentences, nil
+}
+
+// cue is a single cleaned subtitle, along with when it is shown and who is speaking.
+type cue struct {
+	text    string
+	speaker string
+	start   Timecode
+	end     Timecode
+}
+
+// cleanSubtitle joins the lines of a single subtitle, normalises it with normaliser, and removes
+// anything matched by `RemoveFromSubtitleRegexp`.
+func cleanSubtitle(subtitle srt.Subtitle, normaliser Normaliser) string {
+	line := normaliser.Normalise(strings.Join(subtitle.Line, " "))
+	return RemoveFromSubtitleRegexp.ReplaceAllString(line, "")
+}
+
+// joinSentences builds sentences from cleaned subtitles. A sentence begins on a subtitle matching
+// `StartToken` and continues across the following subtitles until `EndToken` is matched. The
+// sentence takes its speaker and start time from the first subtitle, and its stop time from the
+// last.
+func joinSentences(lines []cue) (sentences []Sentence) {
 	for index, line := range lines {
-		if StartToken.MatchString(line) {
-			if EndToken.MatchString(line) {
-				sentences = append(sentences, line)
+		if StartToken.MatchString(line.text) {
+			sentence := Sentence{Text: line.text, Speaker: line.speaker, Start: line.start, Stop: line.end}
+
+			if EndToken.MatchString(line.text) {
+				sentences = append(sentences, sentence)
 			} else {
 				currentIndex := index
-				currentLine := line
 
 				for {
 					currentIndex = currentIndex + 1
@@ -131,10 +229,11 @@ func Strip(path string) (sentences []string, err error) {
 						break
 					}
 
-					currentLine = strings.Join([]string{currentLine, lines[currentIndex]}, " ")
+					sentence.Text = strings.Join([]string{sentence.Text, lines[currentIndex].text}, " ")
+					sentence.Stop = lines[currentIndex].end
 
-					if EndToken.MatchString(currentLine) {
-						sentences = append(sentences, currentLine)
+					if EndToken.MatchString(sentence.Text) {
+						sentences = append(sentences, sentence)
 						break
 					}
 				}
@@ -142,126 +241,106 @@ func Strip(path string) (sentences []string, err error) {
 		}
 	}
 
-	return sentences, nil
+	return sentences
 }
 
 // PickFromFile is a convenience method to pick a random sentence from a list of sentences
-// found within the file provided by path parameter.
-func PickFromFile(path string, min, max int) (string, error) {
-	src, err := os.Open(path)
+// found within the file provided by path parameter. Options are used to load the corpus, see
+// `LoadCorpus`. Compiled corpus files are read by seeking to the chosen sentence rather than
+// being loaded, unless picks are weighted with `WithScorer`. The path can also be a URL, see
+// `SourceFor`.
+func PickFromFile(path string, min, max int, options ...Option) (string, error) {
+	path, err := resolve(path)
 	if err != nil {
 		return "", err
 	}
 
-	defer src.Close()
+	if IsCompiled(path) && configure(options).scorer == nil {
+		compiled, err := OpenCompiled(path, options...)
+		if err != nil {
+			return "", err
+		}
 
-	var sentences []string
-	scanner := bufio.NewScanner(src)
-	for scanner.Scan() {
-		sentences = append(sentences, scanner.Text())
+		defer compiled.Close()
+		return compiled.Pick(min, max)
 	}
 
-	sentence, err := Pick(sentences, min, max)
+	corpus, err := LoadCorpus(path, options...)
 	if err != nil {
 		return "", err
 	}
 
-	return sentence, nil
+	return corpus.Pick(min, max)
 }
 
 // Pick a random sentence from a collection of sentences provided as the first argument to the
-// method. A minimum and maximum chracter length for the sentence can be used to filter the
-// sentences. Passing a negative value to either one of these will use sensible defaults.
-// Uses the default source for randomisation, it is advised that you seed the default source
-// using something like `rand.Seed(time.Now().UnixNano()` in order to ensure you are picking
-// random values each time, rather than using the same deterministic seed.
-func Pick(sentences []string, min, max int) (string, error) {
-	if len(sentences) == 0 {
-		return "", errors.New("unable to pick from empty sentences slice")
-	}
-
-	n := rand.Intn(len(sentences))
-
-	if min < 0 {
-		min = 0
-	}
-
-	if max < 0 {
-		max = math.MaxInt32
-	}
-
-	if min > max {
-		return "", errors.New("min value must be smaller than max")
-	}
-
-	if max < MinimumLineLength {
-		return "", errors.New("max value must be larger than the minimum sentence length")
-	}
+// method. A minimum and maximum length for the sentence can be used to filter the sentences,
+// measured using `DefaultUnit`. Passing a negative value to either one of these will use sensible
+// defaults. When picking more than once from the same sentences, create a `Corpus` instead.
+// Uses the default source for randomisation, unless a source is given with the `WithSource` or
+// `WithRand` options. Passing a source with a known seed makes the pick reproducible.
+func Pick(sentences []string, min, max int, options ...Option) (string, error) {
+	return NewCorpus(sentences, options...).Pick(min, max)
+}
 
-	if max == -1 {
-		return sentences[n], nil
+// GenerateFromFile is a convenience method to generate a random paragraph from a list of sentences
+// found within the file provided by path parameter. Options are used to load the corpus, see
+// `LoadCorpus`. Compiled corpus files are read by seeking to each chosen sentence rather than
+// being loaded, unless picks are weighted with `WithScorer`. The path can also be a URL, see
+// `SourceFor`.
+func GenerateFromFile(path string, min, max int, options ...Option) (string, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return "", err
 	}
 
-	var filtered []string
-	for _, sentence := range sentences {
-		if len(sentence) > min && len(sentence) < max {
-			filtered = append(filtered, sentence)
+	if IsCompiled(path) && configure(options).scorer == nil {
+		compiled, err := OpenCompiled(path, options...)
+		if err != nil {
+			return "", err
 		}
+
+		defer compiled.Close()
+		return compiled.Generate(min, max)
 	}
 
-	if len(filtered) == 0 {
-		return "", errors.New("no candidates with given min and max values")
+	corpus, err := LoadCorpus(path, options...)
+	if err != nil {
+		return "", err
 	}
 
-	n = rand.Intn(len(filtered))
-	return filtered[n], nil
+	return corpus.Generate(min, max)
 }
 
-// GenerateFromFile is a convenience method to generate a random paragraph from a list of sentences
-// found within the file provided by path parameter.
-func GenerateFromFile(path string, min, max int) (string, error) {
-	src, err := os.Open(path)
+// GenerateCoherentFromFile is a convenience method to generate a paragraph of sentences that follow
+// each other, from the sentences found within the file provided by path parameter. Options are used
+// to load the corpus, see `LoadCorpus`. See `Corpus.GenerateCoherent`.
+func GenerateCoherentFromFile(path string, min, max int, options ...Option) (string, error) {
+	corpus, err := LoadCorpus(path, options...)
 	if err != nil {
 		return "", err
 	}
 
-	defer src.Close()
-
-	var sentences []string
-	scanner := bufio.NewScanner(src)
-	for scanner.Scan() {
-		sentences = append(sentences, scanner.Text())
-	}
+	return corpus.GenerateCoherent(min, max)
+}
 
-	paragraph, err := Generate(sentences, min, max)
+// GenerateExactFromFile is a convenience method to generate a paragraph that fits within the given
+// length range, from the sentences found within the file provided by path parameter. Options are
+// used to load the corpus, see `LoadCorpus`. See `Corpus.GenerateExact`.
+func GenerateExactFromFile(path string, min, max int, options ...Option) (string, error) {
+	corpus, err := LoadCorpus(path, options...)
 	if err != nil {
 		return "", err
 	}
 
-	return paragraph, nil
+	return corpus.GenerateExact(min, max)
 }
 
 // Generate a random paragraph which can consist of one or many random sentences. This uses the
-// `Pick` method to pick a random sentence of a given length. A minimum and maximum character length
-// for the final paragraph can be provided. Passing a negative value to either one of these will
-// use sensible defaults. As this uses `Pick` to determine which random sentence is used, it should
-// be noted that you should seed the default source for randomisation. See `Pick` for more details.
-func Generate(sentences []string, min, max int) (out string, err error) {
-	for {
-		sentence, err := Pick(sentences, -1, max-len(out))
-
-		if err != nil {
-			return out, err
-		}
-
-		out = out + sentence
-
-		if len(out) > min {
-			break
-		}
-
-		out = out + " "
-	}
-
-	return out, nil
+// `Pick` method to pick a random sentence of a given length. A minimum and maximum length for the
+// final paragraph can be provided, measured using `DefaultUnit`. Passing a negative value to either
+// one of these will use sensible defaults. As this uses `Pick` to determine which random sentence
+// is used, the same options for randomisation apply. See `Pick` for more details.
+func Generate(sentences []string, min, max int, options ...Option) (out string, err error) {
+	return NewCorpus(sentences, options...).Generate(min, max)
 }
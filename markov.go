@@ -0,0 +1,292 @@
+package forensicfilescorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+var (
+	// MarkovTries is the number of sentences `Markov.Generate` creates looking for one that fits
+	// the length range and does not copy the corpus, before giving up.
+	MarkovTries = 1000
+
+	// MarkovMaxWords is the most words a generated sentence can have. Chains that have not reached
+	// the end of a sentence by then are abandoned.
+	MarkovMaxWords = 100
+)
+
+// Markov generates new sentences from a word level Markov chain trained on a corpus. Each word is
+// chosen based on the words before it, as many as the order of the chain. Sentences begin with the
+// words that begin a sentence within the corpus, and end where a sentence within the corpus ends,
+// so only sentences matching `StartToken` and `EndToken` are used to train the chain.
+//
+// A chain can be saved with `Write` and loaded again with `ReadMarkov`, so that it does not need to
+// be trained every time.
+type Markov struct {
+	order   int
+	overlap int
+
+	// starts holds the first words of each sentence, and transitions holds every word that follows
+	// each run of words, keyed by the run joined with spaces. The end of a sentence is held as an
+	// empty word. Words are held once per occurrence, so that common words are chosen more often.
+	starts      [][]string
+	transitions map[string][]string
+
+	// sources holds a hash of every sentence, and overlaps holds a hash of every run of overlap+1
+	// words within a sentence, so that generated sentences that copy the corpus can be rejected.
+	sources  map[uint64]bool
+	overlaps map[uint64]bool
+
+	unit Unit
+	rng  *rand.Rand
+}
+
+// NewMarkov trains a chain of the given order on the sentences within corpus. Generated sentences
+// may share no more than overlap consecutive words with any sentence of the corpus, which must be
+// more than the order as every run of order+1 words comes from the corpus. The chain measures
+// sentences and picks words using the unit and randomisation source of the corpus.
+func NewMarkov(corpus *Corpus, order, overlap int) (*Markov, error) {
+	if order < 1 {
+		return nil, errors.New("markov chain order must be at least one")
+	}
+
+	if overlap <= order {
+		return nil, errors.New("overlap must be larger than the markov chain order")
+	}
+
+	m := &Markov{
+		order:       order,
+		overlap:     overlap,
+		transitions: map[string][]string{},
+		sources:     map[uint64]bool{},
+		overlaps:    map[uint64]bool{},
+		unit:        corpus.unit,
+		rng:         corpus.rng,
+	}
+
+	for _, sentence := range corpus.sentences {
+		if !StartToken.MatchString(sentence.Text) || !EndToken.MatchString(sentence.Text) {
+			continue
+		}
+
+		words := strings.Fields(sentence.Text)
+		if len(words) < order {
+			continue
+		}
+
+		m.starts = append(m.starts, words[:order])
+
+		for i := order; i <= len(words); i++ {
+			next := ""
+			if i < len(words) {
+				next = words[i]
+			}
+
+			key := strings.Join(words[i-order:i], " ")
+			m.transitions[key] = append(m.transitions[key], next)
+		}
+
+		m.sources[hashWords(words)] = true
+		for i := 0; i+overlap < len(words); i++ {
+			m.overlaps[hashWords(words[i:i+overlap+1])] = true
+		}
+	}
+
+	if len(m.starts) == 0 {
+		return nil, errors.New("no sentences to train the markov chain with")
+	}
+
+	return m, nil
+}
+
+func hashWords(words []string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(words, " ")))
+	return h.Sum64()
+}
+
+// Order returns the number of words each word of the chain is chosen from.
+func (m *Markov) Order() int {
+	return m.order
+}
+
+// Generate creates a new sentence from the chain. A minimum and maximum length for the sentence
+// can be given, measured in the unit of the chain, with the same rules as `Pick`. Sentences that
+// are within the corpus, or that share more than the allowed overlap of words with a sentence
+// within the corpus, are never returned.
+func (m *Markov) Generate(min, max int) (string, error) {
+	min, max, err := pickRange(min, max, m.unit)
+	if err != nil {
+		return "", err
+	}
+
+	for try := 0; try < MarkovTries; try++ {
+		words, ok := m.walk()
+		if !ok || m.copies(words) {
+			continue
+		}
+
+		text := strings.Join(words, " ")
+		if length := m.unit.Length(text); length > min && length < max {
+			return text, nil
+		}
+	}
+
+	return "", errors.New("unable to generate a new sentence with given min and max values")
+}
+
+// walk follows the chain from a random sentence start until it reaches the end of a sentence,
+// reporting false if the chain is abandoned first.
+func (m *Markov) walk() ([]string, bool) {
+	start := m.starts[intn(m.rng, len(m.starts))]
+	words := append([]string{}, start...)
+
+	for len(words) <= MarkovMaxWords {
+		next := m.transitions[strings.Join(words[len(words)-m.order:], " ")]
+		if len(next) == 0 {
+			return nil, false
+		}
+
+		word := next[intn(m.rng, len(next))]
+		if word == "" {
+			return words, true
+		}
+
+		words = append(words, word)
+	}
+
+	return nil, false
+}
+
+// copies reports whether words is a sentence within the corpus, or shares too many consecutive
+// words with one.
+func (m *Markov) copies(words []string) bool {
+	if m.sources[hashWords(words)] {
+		return true
+	}
+
+	for i := 0; i+m.overlap < len(words); i++ {
+		if m.overlaps[hashWords(words[i:i+m.overlap+1])] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// markovJSON is how a chain is stored by `Markov.Write`.
+type markovJSON struct {
+	Order       int                 `json:"order"`
+	Overlap     int                 `json:"overlap"`
+	Starts      [][]string          `json:"starts"`
+	Transitions map[string][]string `json:"transitions"`
+	Sources     []uint64            `json:"sources"`
+	Overlaps    []uint64            `json:"overlaps"`
+}
+
+// Write saves the chain to w as JSON. The unit and randomisation source are not saved.
+func (m *Markov) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(markovJSON{
+		Order:       m.order,
+		Overlap:     m.overlap,
+		Starts:      m.starts,
+		Transitions: m.transitions,
+		Sources:     sortedHashes(m.sources),
+		Overlaps:    sortedHashes(m.overlaps),
+	})
+}
+
+func sortedHashes(set map[uint64]bool) []uint64 {
+	hashes := make([]uint64, 0, len(set))
+	for hash := range set {
+		hashes = append(hashes, hash)
+	}
+
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i] < hashes[j]
+	})
+
+	return hashes
+}
+
+// WriteFile saves the chain to the file at path, compressed as given by the extension of the path.
+// See `CompressionOf`.
+func (m *Markov) WriteFile(path string) error {
+	dest, err := CreateFile(path, CompressionOf(path))
+	if err != nil {
+		return err
+	}
+
+	if err := m.Write(dest); err != nil {
+		dest.Close()
+		return err
+	}
+
+	return dest.Close()
+}
+
+// ReadMarkov loads a chain saved by `Markov.Write`. Options are used for the unit to measure
+// sentences and the source of randomisation, as with `NewCorpus`.
+func ReadMarkov(r io.Reader, options ...Option) (*Markov, error) {
+	var stored markovJSON
+	if err := json.NewDecoder(r).Decode(&stored); err != nil {
+		return nil, err
+	}
+
+	if stored.Order < 1 || stored.Overlap <= stored.Order || len(stored.Starts) == 0 ||
+		stored.Transitions == nil {
+		return nil, errors.New("not a markov chain")
+	}
+
+	// Generating looks up the last order words of each sentence, so every start needs exactly that
+	// many words.
+	for _, start := range stored.Starts {
+		if len(start) != stored.Order {
+			return nil, errors.New("markov chain start does not match the chain order")
+		}
+	}
+
+	config := configure(options)
+	m := &Markov{
+		order:       stored.Order,
+		overlap:     stored.Overlap,
+		starts:      stored.Starts,
+		transitions: stored.Transitions,
+		sources:     map[uint64]bool{},
+		overlaps:    map[uint64]bool{},
+		unit:        config.unit,
+		rng:         config.rng,
+	}
+
+	for _, hash := range stored.Sources {
+		m.sources[hash] = true
+	}
+
+	for _, hash := range stored.Overlaps {
+		m.overlaps[hash] = true
+	}
+
+	return m, nil
+}
+
+// ReadMarkovFile loads a chain from the file provided by path parameter. See `ReadMarkov`.
+func ReadMarkovFile(path string, options ...Option) (*Markov, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	return ReadMarkov(src, options...)
+}
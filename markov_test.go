@@ -0,0 +1,223 @@
+package forensicfilescorpus
+
+import (
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var markovSentences = []string{
+	"Police found the car near the river.",
+	"Detectives searched the house near the school.",
+	"The victim left the house before dawn.",
+	"Police questioned the neighbour before the trial.",
+	"Detectives found fibers on the car seat.",
+	"The neighbour searched the river bank.",
+	"lowercase sentences are not used to train the chain.",
+	"Sentences without an ending are not used either",
+}
+
+// sharesRun reports whether words shares a run of n consecutive words with any of the sentences.
+func sharesRun(words []string, sentences []string, n int) bool {
+	for i := 0; i+n <= len(words); i++ {
+		run := " " + strings.Join(words[i:i+n], " ") + " "
+		for _, sentence := range sentences {
+			if strings.Contains(" "+sentence+" ", run) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func TestNewMarkovInvalid(t *testing.T) {
+	tests := []struct {
+		name           string
+		sentences      []string
+		order, overlap int
+	}{
+		{"zero order", markovSentences, 0, 2},
+		{"overlap equal to order", markovSentences, 2, 2},
+		{"overlap smaller than order", markovSentences, 2, 1},
+		{"no usable sentences", markovSentences[6:], 1, 3},
+		{"sentences shorter than the order", []string{"He ran.", "Why?"}, 3, 4},
+	}
+
+	for _, test := range tests {
+		if _, err := NewMarkov(NewCorpus(test.sentences), test.order, test.overlap); err == nil {
+			t.Errorf("%s: NewMarkov(%d, %d) error = nil, want an error", test.name, test.order, test.overlap)
+		}
+	}
+}
+
+func TestMarkovGenerate(t *testing.T) {
+	for _, order := range []int{1, 2} {
+		corpus := NewCorpus(markovSentences, WithSource(rand.NewSource(1)))
+		m, err := NewMarkov(corpus, order, order+2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Order() != order {
+			t.Errorf("Order() = %d, want %d", m.Order(), order)
+		}
+
+		for i := 0; i < 20; i++ {
+			text, err := m.Generate(20, 60)
+			if err != nil {
+				t.Fatalf("order %d: Generate(20, 60) error = %v", order, err)
+			}
+
+			words := strings.Fields(text)
+			if containsString(markovSentences, text) {
+				t.Errorf("order %d: Generate() = %q, want a sentence not within the corpus", order, text)
+			}
+
+			if sharesRun(words, markovSentences, order+3) {
+				t.Errorf("order %d: Generate() = %q, want no more than %d words in a row from the corpus", order, text, order+2)
+			}
+
+			if !StartToken.MatchString(text) || !EndToken.MatchString(text) {
+				t.Errorf("order %d: Generate() = %q, want a whole sentence", order, text)
+			}
+
+			if length := DefaultUnit.Length(text); length <= 20 || length >= 60 {
+				t.Errorf("order %d: Generate() = %q with length %d, want within 20 and 60", order, text, length)
+			}
+		}
+	}
+}
+
+func TestMarkovGenerateRejectsOverlap(t *testing.T) {
+	sentences := []string{"The man ran away.", "The dog ran home."}
+
+	tests := []struct {
+		overlap int
+		want    []string
+	}{
+		// Every new sentence shares three words with one of the corpus.
+		{2, nil},
+		{3, []string{"The man ran home.", "The dog ran away."}},
+	}
+
+	for _, test := range tests {
+		m, err := NewMarkov(NewCorpus(sentences, WithSource(rand.NewSource(1))), 1, test.overlap)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := m.Generate(-1, -1)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("overlap %d: Generate() = %q, want an error", test.overlap, got)
+			}
+
+			continue
+		}
+
+		if err != nil || !containsString(test.want, got) {
+			t.Errorf("overlap %d: Generate() = %q, %v, want one of %q", test.overlap, got, err, test.want)
+		}
+	}
+}
+
+func TestMarkovGenerateInvalidRange(t *testing.T) {
+	m, err := NewMarkov(NewCorpus(markovSentences), 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		min, max int
+	}{
+		{30, 20},
+		{-1, 5},
+		{500, -1},
+	}
+
+	for _, test := range tests {
+		got, err := m.Generate(test.min, test.max)
+		if err == nil {
+			t.Errorf("Generate(%d, %d) = %q, want an error", test.min, test.max, got)
+			continue
+		}
+
+		// Invalid values are reported with the same error as every other way of picking.
+		if _, _, invalid := pickRange(test.min, test.max, DefaultUnit); invalid != nil && err.Error() != invalid.Error() {
+			t.Errorf("Generate(%d, %d) error = %v, want %v", test.min, test.max, err, invalid)
+		}
+	}
+}
+
+func TestMarkovWriteRead(t *testing.T) {
+	trained, err := NewMarkov(NewCorpus(markovSentences, WithSource(rand.NewSource(1))), 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "chain.json.gz")
+	if err := trained.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := ReadMarkovFile(path, WithSource(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Order() != trained.Order() {
+		t.Errorf("ReadMarkovFile() Order() = %d, want %d", loaded.Order(), trained.Order())
+	}
+
+	// With the same source, the loaded chain makes the same choices as the trained one.
+	for i := 0; i < 10; i++ {
+		want, wantErr := trained.Generate(-1, -1)
+		got, err := loaded.Generate(-1, -1)
+
+		if got != want || (err == nil) != (wantErr == nil) {
+			t.Errorf("ReadMarkovFile() Generate() = %q, %v, want %q, %v", got, err, want, wantErr)
+		}
+	}
+
+	// The hashes of the corpus are kept, so loaded chains still reject copies.
+	for _, sentence := range markovSentences[:6] {
+		if !loaded.copies(strings.Fields(sentence)) {
+			t.Errorf("ReadMarkovFile() copies(%q) = false, want true", sentence)
+		}
+	}
+}
+
+func TestReadMarkovInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not json", "order: 1"},
+		{"empty", "{}"},
+		{"zero order", `{"order":0,"overlap":2,"starts":[["He"]],"transitions":{}}`},
+		{"overlap not larger than order", `{"order":2,"overlap":2,"starts":[["He","ran"]],"transitions":{}}`},
+		{"no starts", `{"order":1,"overlap":2,"starts":[],"transitions":{"He":["ran"]}}`},
+		{"no transitions", `{"order":1,"overlap":2,"starts":[["He"]]}`},
+		{"start shorter than order", `{"order":2,"overlap":3,"starts":[["He"]],"transitions":{"He":["ran"]}}`},
+		{"start longer than order", `{"order":1,"overlap":3,"starts":[["He","ran"]],"transitions":{"ran":[""]}}`},
+	}
+
+	for _, test := range tests {
+		if _, err := ReadMarkov(strings.NewReader(test.input)); err == nil {
+			t.Errorf("%s: ReadMarkov() error = nil, want an error", test.name)
+		}
+	}
+
+	valid := strings.NewReader(`{"order":1,"overlap":2,"starts":[["He"]],"transitions":{"He":["ran"],"ran":["away."],"away.":[""]}}`)
+
+	m, err := ReadMarkov(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := m.Generate(-1, -1); err != nil || got != "He ran away." {
+		t.Errorf("ReadMarkov() Generate() = %q, %v, want %q", got, err, "He ran away.")
+	}
+}
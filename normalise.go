@@ -0,0 +1,135 @@
+package forensicfilescorpus
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// EllipsisMode determines how an ellipsis is written once a subtitle has been normalised.
+type EllipsisMode int
+
+const (
+	// EllipsisKeep leaves any ellipsis as it was written in the subtitle.
+	EllipsisKeep EllipsisMode = iota
+	// EllipsisASCII writes every ellipsis as three full stops, "...".
+	EllipsisASCII
+	// EllipsisUnicode writes every ellipsis as the single "…" character.
+	EllipsisUnicode
+)
+
+// Normaliser cleans up the typography of subtitles before they are matched against the start and
+// end tokens. Subtitles come from many sources, and mix straight and curly quotes, "..." and "…",
+// en and em dashes, non-breaking spaces and music notes. Each stage can be turned on or off.
+type Normaliser struct {
+	// NFC puts text into Unicode Normalization Form C, so that letters followed by combining
+	// accents are composed into their precomposed form, and "e" followed by U+0301 becomes "é".
+	NFC bool
+	// FoldQuotes replaces curly and angled quotation marks with their straight equivalents.
+	FoldQuotes bool
+	// FoldDashes replaces en dashes, em dashes and other dash variants with a hyphen.
+	FoldDashes bool
+	// StripMusicNotes removes music note characters that surround sung lyrics, such as "♪".
+	StripMusicNotes bool
+	// CollapseWhitespace replaces unicode spaces with a plain space, removes zero width
+	// characters, and collapses runs of whitespace into a single space.
+	CollapseWhitespace bool
+	// Ellipsis determines how an ellipsis is written.
+	Ellipsis EllipsisMode
+}
+
+// DefaultNormaliser returns the normaliser used by `Strip` to normalise every subtitle before it is
+// matched, which runs every stage and writes each ellipsis as three full stops. Use
+// `WithNormaliser` to strip with different stages.
+func DefaultNormaliser() Normaliser {
+	return Normaliser{
+		NFC:                true,
+		FoldQuotes:         true,
+		FoldDashes:         true,
+		StripMusicNotes:    true,
+		CollapseWhitespace: true,
+		Ellipsis:           EllipsisASCII,
+	}
+}
+
+// WithNormaliser sets the normaliser used to clean each subtitle when stripping subtitle files. By
+// default subtitles are stripped with `DefaultNormaliser`.
+func WithNormaliser(normaliser Normaliser) Option {
+	return func(c *Corpus) {
+		c.normaliser = normaliser
+	}
+}
+
+var quoteFolder = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'", "‹", "'", "›", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`, "″", `"`, "«", `"`, "»", `"`,
+)
+
+var dashFolder = strings.NewReplacer(
+	"‐", "-", "‑", "-", "‒", "-", "–", "-", "—", "-", "―", "-", "−", "-",
+)
+
+var musicNotes = strings.NewReplacer(
+	"♩", "", "♪", "", "♫", "", "♬", "", "\U0001F3B5", "", "\U0001F3B6", "",
+)
+
+var spacedEllipsisRegexp = regexp.MustCompile(`\.( ?\.){2,}`)
+
+// Normalise runs each of the enabled normalisation stages over s.
+func (n Normaliser) Normalise(s string) string {
+	if n.NFC {
+		s = norm.NFC.String(s)
+	}
+
+	if n.FoldQuotes {
+		s = quoteFolder.Replace(s)
+	}
+
+	if n.FoldDashes {
+		s = dashFolder.Replace(s)
+	}
+
+	if n.StripMusicNotes {
+		s = musicNotes.Replace(s)
+	}
+
+	switch n.Ellipsis {
+	case EllipsisASCII:
+		s = strings.Replace(s, "…", "...", -1)
+		s = spacedEllipsisRegexp.ReplaceAllString(s, "...")
+	case EllipsisUnicode:
+		s = spacedEllipsisRegexp.ReplaceAllString(s, "…")
+	}
+
+	if n.CollapseWhitespace {
+		s = collapseWhitespace(s)
+	}
+
+	return s
+}
+
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	space := false
+
+	for _, r := range s {
+		switch {
+		case r == '\u200B' || r == '\u200C' || r == '\u2060' || r == '\uFEFF':
+			continue
+		case unicode.IsSpace(r) || unicode.Is(unicode.Zs, r):
+			space = true
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		space = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
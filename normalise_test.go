@@ -0,0 +1,68 @@
+package forensicfilescorpus
+
+import "testing"
+
+func TestNormalise(t *testing.T) {
+	all := DefaultNormaliser()
+
+	unicodeEllipsis := DefaultNormaliser()
+	unicodeEllipsis.Ellipsis = EllipsisUnicode
+
+	tests := []struct {
+		name       string
+		normaliser Normaliser
+		in         string
+		want       string
+	}{
+		{"plain", all, "It was a cold night.", "It was a cold night."},
+		{"combining acute", all, "Jose\u0301 Nu\u0301n\u0303ez", "Jos\u00e9 N\u00fa\u00f1ez"},
+		{"outside latin", all, "\u0430\u0306", "\u04d1"},
+		{"stacked marks", all, "\u00e2\u0323", "\u1ead"},
+		{"reordered marks", all, "a\u0302\u0323", "\u1ead"},
+		{"nfc off", Normaliser{}, "Jose\u0301", "Jose\u0301"},
+		{"curly quotes", all, "“It’s over,” he said.", `"It's over," he said.`},
+		{"dashes", all, "Then—nothing – at all", "Then-nothing - at all"},
+		{"music notes", all, "♪ Forensic Files ♪", "Forensic Files"},
+		{"whitespace", all, "Two \u00a0spaces\u200b here\t", "Two spaces here"},
+		{"ellipsis ascii", all, "Well… I . . . don't know", "Well... I ... don't know"},
+		{"ellipsis unicode", unicodeEllipsis, "Well... I . . . don't know", "Well… I … don't know"},
+		{"ellipsis keep", Normaliser{}, "Well…", "Well…"},
+	}
+
+	for _, test := range tests {
+		if got := test.normaliser.Normalise(test.in); got != test.want {
+			t.Errorf("%s: Normalise(%q) = %q, want %q", test.name, test.in, got, test.want)
+		}
+	}
+}
+
+func TestStripWithNormaliser(t *testing.T) {
+	path := writeSubtitles(t, t.TempDir(), "curly.srt", "The detective told him", "“It’s over.”", "The jury agreed.")
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+	}{
+		{"default", nil, []string{`The detective told him "It's over."`, "The jury agreed."}},
+		{"quotes only", []Option{WithNormaliser(Normaliser{FoldQuotes: true})}, []string{`The detective told him "It's over."`, "The jury agreed."}},
+		{"none", []Option{WithNormaliser(Normaliser{})}, []string{"The detective told him “It’s over.”", "The jury agreed."}},
+	}
+
+	for _, test := range tests {
+		got, err := Strip(path, test.options...)
+		if err != nil {
+			t.Fatalf("%s: Strip() error = %v", test.name, err)
+		}
+
+		if len(got) != len(test.want) {
+			t.Fatalf("%s: Strip() = %q, want %q", test.name, got, test.want)
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: Strip()[%d] = %q, want %q", test.name, i, got[i], test.want[i])
+			}
+		}
+	}
+}
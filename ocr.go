@@ -0,0 +1,264 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Confusion is a pair of strings that are commonly mistaken for each other when subtitles are
+// OCR'd from DVD bitmap subtitles. `From` is what the OCR produced and `To` is what was meant.
+// `Weight` is how much we trust the confusion, between 0 and 1. Pairs where both sides are
+// plausible letters, such as "cl" and "d", are weighted lower as they are also found in real words.
+type Confusion struct {
+	From   string
+	To     string
+	Weight float64
+}
+
+// OCRConfusions are the known confusion pairs used when correcting OCR'd subtitles.
+var OCRConfusions = []Confusion{
+	{"l", "I", 0.99},
+	{"|", "I", 1},
+	{"0", "O", 0.99},
+	{"0", "o", 0.99},
+	{"rn", "m", 0.97},
+	{"vv", "w", 0.97},
+	{"1", "I", 0.9},
+	{"1", "l", 0.9},
+	{"I", "l", 0.9},
+	{"5", "S", 0.9},
+	{"cl", "d", 0.9},
+	{"ii", "n", 0.9},
+}
+
+// ocrWordRegexp matches the words within a sentence that are candidates for correction. This
+// includes "|" and digits as they are often what the OCR produced in place of a letter.
+var ocrWordRegexp = regexp.MustCompile(`[\p{L}\p{N}|']+`)
+
+// Vocabulary is the number of times each lowercased word appears within a corpus.
+type Vocabulary map[string]int
+
+// BuildVocabulary counts the words found within sentences. As OCR errors are rare compared to the
+// correctly recognised words, a vocabulary built from the corpus itself is a good guide as to what
+// a word should have been.
+func BuildVocabulary(sentences []string) Vocabulary {
+	vocabulary := make(Vocabulary)
+
+	for _, sentence := range sentences {
+		for _, word := range ocrWordRegexp.FindAllString(sentence, -1) {
+			vocabulary[strings.ToLower(word)]++
+		}
+	}
+
+	return vocabulary
+}
+
+// Correction is a single suggested change to a word within a sentence. `Confidence` is between
+// 0 and 1, and is how much more common the suggested word is than the original, scaled by the
+// weight of the confusions used to reach it.
+type Correction struct {
+	Sentence   int
+	Offset     int
+	Original   string
+	Suggested  string
+	Confidence float64
+}
+
+// Dictionary is a set of lowercased words known to be spelt correctly.
+type Dictionary map[string]bool
+
+// ReadDictionary reads a dictionary from r, with one word on each line, such as
+// "/usr/share/dict/words".
+func ReadDictionary(r io.Reader) (Dictionary, error) {
+	dictionary := make(Dictionary)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			dictionary[strings.ToLower(word)] = true
+		}
+	}
+
+	return dictionary, scanner.Err()
+}
+
+// ReadDictionaryFile reads a dictionary from the file provided by path parameter. See
+// `ReadDictionary`.
+func ReadDictionaryFile(path string) (Dictionary, error) {
+	src, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	return ReadDictionary(src)
+}
+
+// Corrector fixes OCR errors within sentences using a vocabulary and a table of confusions.
+//
+// A systematic OCR error, such as "lt" for "It", can appear many times within a corpus, so how
+// often a word appears says nothing about whether it is spelt correctly. Instead a word is
+// corrected when a confusion turns it into a word that appears more often, and the confidence of
+// the correction grows with how much more often. Words within the dictionary are never corrected.
+type Corrector struct {
+	Vocabulary Vocabulary
+	Confusions []Confusion
+
+	// Dictionary holds real words, which are never corrected and can be suggested however rarely
+	// they appear. It is optional, but without it rare real words that look like an OCR error of
+	// a common word, such as "clog" for "dog", are listed for review.
+	Dictionary Dictionary
+
+	// KnownCount is the number of times a word outside of the dictionary must appear in the
+	// vocabulary to be suggested as a correction.
+	KnownCount int
+
+	// Threshold is the confidence at which a correction is applied automatically. Anything below
+	// this is returned for review instead.
+	Threshold float64
+}
+
+// NewCorrector creates a corrector with a vocabulary built from the given sentences and the
+// default `OCRConfusions`.
+func NewCorrector(sentences []string) *Corrector {
+	return &Corrector{
+		Vocabulary: BuildVocabulary(sentences),
+		Confusions: OCRConfusions,
+		KnownCount: 5,
+		Threshold:  0.95,
+	}
+}
+
+// Correct fixes the OCR errors within a sentence. Corrections with a confidence at or above the
+// threshold are applied to the returned sentence, and the rest are returned for review.
+func (c *Corrector) Correct(sentence string) (corrected string, applied, review []Correction) {
+	var b strings.Builder
+	last := 0
+
+	for _, loc := range ocrWordRegexp.FindAllStringIndex(sentence, -1) {
+		word := sentence[loc[0]:loc[1]]
+		correction, ok := c.suggest(word)
+
+		if !ok {
+			continue
+		}
+
+		correction.Offset = loc[0]
+
+		if correction.Confidence < c.Threshold {
+			review = append(review, correction)
+			continue
+		}
+
+		applied = append(applied, correction)
+		b.WriteString(sentence[last:loc[0]])
+		b.WriteString(correction.Suggested)
+		last = loc[1]
+	}
+
+	b.WriteString(sentence[last:])
+
+	return b.String(), applied, review
+}
+
+// CorrectAll runs `Correct` over every sentence, returning the corrected sentences and every
+// correction that was not confident enough to be applied.
+func (c *Corrector) CorrectAll(sentences []string) (corrected []string, review []Correction) {
+	for i, sentence := range sentences {
+		sentence, _, low := c.Correct(sentence)
+
+		for _, correction := range low {
+			correction.Sentence = i
+			review = append(review, correction)
+		}
+
+		corrected = append(corrected, sentence)
+	}
+
+	return corrected, review
+}
+
+// suggest finds the most common known word that can be made from word by replacing up to two
+// confusions, and that appears more often than word itself. Words within the dictionary are left
+// alone.
+func (c *Corrector) suggest(word string) (Correction, bool) {
+	if c.Dictionary[strings.ToLower(word)] {
+		return Correction{}, false
+	}
+
+	original := c.Vocabulary[strings.ToLower(word)]
+	best := Correction{Original: word}
+
+	for candidate, weight := range c.candidates(word, 2) {
+		count := c.Vocabulary[strings.ToLower(candidate)]
+
+		if !c.known(candidate, count) || count <= original || !plausibleCase(candidate) {
+			continue
+		}
+
+		confidence := weight * float64(count) / float64(count+original)
+
+		if confidence > best.Confidence {
+			best.Suggested = candidate
+			best.Confidence = confidence
+		}
+	}
+
+	return best, best.Suggested != ""
+}
+
+// known reports whether candidate, which appears count times within the vocabulary, is a real word.
+func (c *Corrector) known(candidate string, count int) bool {
+	return count >= c.KnownCount || c.Dictionary[strings.ToLower(candidate)]
+}
+
+// candidates returns every string that can be made from word by replacing one occurrence of a
+// confusion at a time, up to depth replacements, along with the combined weight of the confusions.
+func (c *Corrector) candidates(word string, depth int) map[string]float64 {
+	all := make(map[string]float64)
+	current := map[string]float64{word: 1}
+
+	for ; depth > 0; depth-- {
+		next := make(map[string]float64)
+
+		for w, weight := range current {
+			for _, confusion := range c.Confusions {
+				for i := 0; i+len(confusion.From) <= len(w); i++ {
+					if w[i:i+len(confusion.From)] != confusion.From {
+						continue
+					}
+
+					candidate := w[:i] + confusion.To + w[i+len(confusion.From):]
+					candidateWeight := weight * confusion.Weight
+
+					if candidate != word && candidateWeight > all[candidate] {
+						all[candidate] = candidateWeight
+						next[candidate] = candidateWeight
+					}
+				}
+			}
+		}
+
+		current = next
+	}
+
+	return all
+}
+
+// plausibleCase reports whether a word is written in lowercase, uppercase, or with only the first
+// letter capitalised. A confusion that produces "maIl" from "mall" is not a plausible correction.
+func plausibleCase(word string) bool {
+	if word == strings.ToLower(word) || word == strings.ToUpper(word) {
+		return true
+	}
+
+	first, size := utf8.DecodeRuneInString(word)
+	rest := word[size:]
+
+	return unicode.IsUpper(first) && rest == strings.ToLower(rest)
+}
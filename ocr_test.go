@@ -0,0 +1,81 @@
+package forensicfilescorpus
+
+import (
+	"strings"
+	"testing"
+)
+
+// ocrSentences returns a corpus where the OCR error "lt" for "It" is systematic, appearing far more
+// often than `KnownCount`, but far less often than the word it should have been.
+func ocrSentences() []string {
+	var sentences []string
+
+	for i := 0; i < 200; i++ {
+		sentences = append(sentences, "It was a dog. It was found by the river.")
+	}
+
+	for i := 0; i < 10; i++ {
+		sentences = append(sentences, "lt was cold.")
+	}
+
+	return append(sentences, "The drain was full of clog.", "The modem was rnissing.", "The part was missing.", "The key was missing.")
+}
+
+func TestCorrect(t *testing.T) {
+	corrector := NewCorrector(ocrSentences())
+
+	tests := []struct {
+		name       string
+		dictionary Dictionary
+		in         string
+		want       string
+		review     []string
+	}{
+		{"systematic error", nil, "lt was cold.", "It was cold.", nil},
+		{"correct word", nil, "It was a dog.", "It was a dog.", nil},
+		{"rare real word", nil, "The drain was full of clog.", "The drain was full of clog.", []string{"clog"}},
+		{"dictionary word", Dictionary{"clog": true}, "The drain was full of clog.", "The drain was full of clog.", nil},
+		{"unknown suggestion", nil, "The modem was rnissing.", "The modem was rnissing.", nil},
+		{"dictionary suggestion", Dictionary{"missing": true}, "The modem was rnissing.", "The modem was rnissing.", []string{"rnissing"}},
+	}
+
+	for _, test := range tests {
+		corrector.Dictionary = test.dictionary
+		got, _, review := corrector.Correct(test.in)
+
+		if got != test.want {
+			t.Errorf("%s: Correct(%q) = %q, want %q", test.name, test.in, got, test.want)
+		}
+
+		var originals []string
+		for _, correction := range review {
+			originals = append(originals, correction.Original)
+		}
+
+		if strings.Join(originals, ",") != strings.Join(test.review, ",") {
+			t.Errorf("%s: Correct(%q) review = %v, want %v", test.name, test.in, originals, test.review)
+		}
+	}
+}
+
+func TestCorrectAll(t *testing.T) {
+	sentences := ocrSentences()
+	corrected, _ := NewCorrector(sentences).CorrectAll(sentences)
+
+	for i, sentence := range corrected {
+		if strings.HasPrefix(sentence, "lt ") {
+			t.Errorf("CorrectAll()[%d] = %q, want the OCR error corrected", i, sentence)
+		}
+	}
+}
+
+func TestReadDictionary(t *testing.T) {
+	dictionary, err := ReadDictionary(strings.NewReader("Clog\n\n  dog \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dictionary) != 2 || !dictionary["clog"] || !dictionary["dog"] {
+		t.Errorf("ReadDictionary() = %v, want clog and dog", dictionary)
+	}
+}
@@ -0,0 +1,120 @@
+package forensicfilescorpus
+
+import "errors"
+
+// Diversity groups sentences so that a batch pick can spread its sentences across the groups, see
+// `Corpus.PickNDiverse`.
+type Diversity func(Sentence) string
+
+var (
+	// ByEpisode spreads a batch pick across episodes.
+	ByEpisode Diversity = func(sentence Sentence) string { return sentence.Episode }
+
+	// BySpeaker spreads a batch pick across speakers.
+	BySpeaker Diversity = func(sentence Sentence) string { return sentence.Speaker }
+)
+
+// PickN picks n distinct random sentences from the corpus, without replacement, from the sentences
+// accepted by every one of filters. Sentences are distinct by their text, so a sentence that appears
+// more than once within the corpus is only picked once. Use `Between` to pick within a length
+// range, as with `Pick`. A filter given to the corpus with `WithFilter` also applies. If fewer than
+// n distinct sentences are accepted then all of them are returned, in a random order.
+func (c *Corpus) PickN(n int, filters ...Filter) ([]Sentence, error) {
+	return c.PickNDiverse(n, nil, filters...)
+}
+
+// PickNDiverse picks n distinct random sentences in the same way as `PickN`, spreading them across
+// the groups given by diversity, such as `ByEpisode`. Every group is used once before any group is
+// used again, so a batch only has two sentences from the same episode when there are fewer episodes
+// than sentences. Sentences without the metadata are grouped together. A nil diversity is the same
+// as `PickN`.
+func (c *Corpus) PickNDiverse(n int, diversity Diversity, filters ...Filter) ([]Sentence, error) {
+	if n < 1 {
+		return nil, errors.New("must pick at least one sentence")
+	}
+
+	filter := All(append([]Filter{c.filter}, filters...)...)
+
+	var candidates []int
+	for i, sentence := range c.sentences {
+		if filter(sentence) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("no candidates with given filters")
+	}
+
+	for i := len(candidates) - 1; i > 0; i-- {
+		j := c.intn(i + 1)
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+
+	candidates = c.distinct(candidates)
+
+	if diversity != nil {
+		candidates = c.interleave(candidates, diversity)
+	}
+
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+
+	picked := make([]Sentence, len(candidates))
+	for i, candidate := range candidates {
+		picked[i] = c.sentences[candidate]
+	}
+
+	return picked, nil
+}
+
+// distinct removes candidates with the same text as an earlier candidate. As the candidates are
+// shuffled, which of the duplicates is kept is random.
+func (c *Corpus) distinct(candidates []int) []int {
+	seen := map[string]bool{}
+	kept := candidates[:0]
+
+	for _, candidate := range candidates {
+		text := c.sentences[candidate].Text
+
+		if !seen[text] {
+			seen[text] = true
+			kept = append(kept, candidate)
+		}
+	}
+
+	return kept
+}
+
+// interleave reorders shuffled candidates so that they take turns between the groups given by
+// diversity. Groups take turns in the order they first appear, which is random as the candidates
+// are shuffled.
+func (c *Corpus) interleave(candidates []int, diversity Diversity) []int {
+	var groups [][]int
+	index := map[string]int{}
+
+	for _, candidate := range candidates {
+		key := diversity(c.sentences[candidate])
+
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, nil)
+		}
+
+		groups[g] = append(groups[g], candidate)
+	}
+
+	interleaved := make([]int, 0, len(candidates))
+	for round := 0; len(interleaved) < len(candidates); round++ {
+		for _, group := range groups {
+			if round < len(group) {
+				interleaved = append(interleaved, group[round])
+			}
+		}
+	}
+
+	return interleaved
+}
@@ -0,0 +1,154 @@
+package forensicfilescorpus
+
+import (
+	"testing"
+)
+
+var diverseSentences = []Sentence{
+	{Text: "He ran away.", Episode: "s01e01", Speaker: "Narrator"},
+	{Text: "The van was found.", Episode: "s01e01", Speaker: "Narrator"},
+	{Text: "Police searched the river bank.", Episode: "s01e01"},
+	{Text: "Fibers were found on the victim's coat.", Episode: "s01e02", Speaker: "Skip Palenik"},
+	{Text: "Is this the man?", Episode: "s01e02"},
+	{Text: "The carpet was new.", Episode: "s01e03", Speaker: "Narrator"},
+}
+
+func TestPickN(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentences []string
+		options   []Option
+		n         int
+		filters   []Filter
+		want      int
+		wantErr   bool
+	}{
+		{name: "fewer than the corpus", sentences: testSentences, n: 3, want: 3},
+		{name: "all of the corpus", sentences: testSentences, n: 5, want: 5},
+		{name: "more than the corpus", sentences: testSentences, n: 10, want: 5},
+		{name: "length range", sentences: testSentences, n: 10, filters: []Filter{Between(15, 35)}, want: 3},
+		{
+			name:      "corpus filter",
+			sentences: testSentences,
+			options:   []Option{WithFilter(Contains("found"))},
+			n:         10,
+			filters:   []Filter{Between(-1, 30)},
+			want:      1,
+		},
+		{
+			name:      "duplicate texts",
+			sentences: []string{"He ran away.", "He ran away.", "Is this the man?", "He ran away."},
+			n:         3,
+			want:      2,
+		},
+		{name: "nothing to pick", sentences: testSentences, n: 0, wantErr: true},
+		{name: "no candidates", sentences: testSentences, n: 1, filters: []Filter{Between(100, -1)}, wantErr: true},
+		{name: "empty corpus", sentences: nil, n: 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		corpus := NewCorpus(test.sentences, test.options...)
+
+		for i := 0; i < 20; i++ {
+			picked, err := corpus.PickN(test.n, test.filters...)
+			if (err != nil) != test.wantErr {
+				t.Errorf("%s: PickN() error = %v, want error %v", test.name, err, test.wantErr)
+				break
+			}
+
+			if len(picked) != test.want {
+				t.Errorf("%s: PickN() = %d sentences, want %d", test.name, len(picked), test.want)
+			}
+
+			seen := map[string]bool{}
+			for _, sentence := range picked {
+				if seen[sentence.Text] {
+					t.Errorf("%s: PickN() picked %q more than once", test.name, sentence.Text)
+				}
+
+				seen[sentence.Text] = true
+
+				if !containsString(test.sentences, sentence.Text) {
+					t.Errorf("%s: PickN() = %q, not in the corpus", test.name, sentence.Text)
+				}
+			}
+		}
+	}
+}
+
+func TestPickNDoesNotModifyFilters(t *testing.T) {
+	corpus := NewCorpus(testSentences, WithFilter(EndsWith(".")))
+
+	filters := make([]Filter, 1, 2)
+	filters[0] = Between(-1, 20)
+
+	if _, err := corpus.PickN(2, filters...); err != nil {
+		t.Fatal(err)
+	}
+
+	if extra := filters[:2][1]; extra != nil {
+		t.Errorf("PickN() wrote into the spare capacity of its filters")
+	}
+}
+
+func TestPickNDiverse(t *testing.T) {
+	tests := []struct {
+		name      string
+		diversity Diversity
+		n         int
+		max       int
+	}{
+		// Each of the three episodes is used once before any is used again.
+		{"episodes", ByEpisode, 3, 1},
+		{"episodes repeat once every episode is used", ByEpisode, 5, 2},
+		// Sentences without a speaker are grouped together as a third speaker.
+		{"speakers", BySpeaker, 3, 1},
+		{"speakers repeat once every speaker is used", BySpeaker, 6, 3},
+	}
+
+	corpus := NewCorpusFromSentences(diverseSentences)
+
+	for _, test := range tests {
+		for i := 0; i < 50; i++ {
+			picked, err := corpus.PickNDiverse(test.n, test.diversity)
+			if err != nil {
+				t.Fatalf("%s: PickNDiverse() error = %v", test.name, err)
+			}
+
+			if len(picked) != test.n {
+				t.Fatalf("%s: PickNDiverse() = %d sentences, want %d", test.name, len(picked), test.n)
+			}
+
+			groups := map[string]int{}
+			for _, sentence := range picked {
+				groups[test.diversity(sentence)]++
+			}
+
+			for group, count := range groups {
+				if count > test.max {
+					t.Errorf("%s: PickNDiverse() picked %d from %q, want at most %d", test.name, count, group, test.max)
+				}
+			}
+		}
+	}
+}
+
+func TestPickNDiverseDuplicateTexts(t *testing.T) {
+	// The same sentence in two episodes is only picked once, leaving room for another sentence.
+	corpus := NewCorpusFromSentences([]Sentence{
+		{Text: "He ran away.", Episode: "s01e01"},
+		{Text: "He ran away.", Episode: "s01e02"},
+		{Text: "Is this the man?", Episode: "s01e02"},
+	})
+
+	for i := 0; i < 20; i++ {
+		picked, err := corpus.PickNDiverse(2, ByEpisode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(picked) != 2 || picked[0].Text == picked[1].Text {
+			t.Errorf("PickNDiverse() = %+v, want two different sentences", picked)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package forensicfilescorpus
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Platform is a place sentences are posted to, along with the character limit for a post and
+// the unit the platform uses to count characters towards that limit.
+type Platform struct {
+	Name  string
+	Limit int
+	Unit  Unit
+}
+
+var (
+	// TwitterUnit measures sentences the way Twitter does. See `TwitterLength`.
+	TwitterUnit = Unit{"twitter", TwitterLength, MinimumLineLength}
+
+	// MastodonUnit measures sentences the way Mastodon does. See `MastodonLength`.
+	MastodonUnit = Unit{"mastodon", MastodonLength, MinimumLineLength}
+)
+
+var (
+	// Twitter allows 280 weighted characters per tweet.
+	Twitter = Platform{"twitter", 280, TwitterUnit}
+
+	// Mastodon allows 500 characters per toot on a default instance.
+	Mastodon = Platform{"mastodon", 500, MastodonUnit}
+)
+
+// Platforms are the available platform presets.
+var Platforms = []Platform{Twitter, Mastodon}
+
+// TransformedURLLength is the length every URL counts as on both Twitter and Mastodon, regardless
+// of how long the URL actually is, as they are shortened before posting.
+const TransformedURLLength = 23
+
+// urlRegexp matches URLs as they are detected by Twitter and Mastodon. This is a simplification of
+// the twitter-text rules, covering URLs with a scheme, URLs starting with "www.", and bare domains
+// on common top level domains.
+var urlRegexp = regexp.MustCompile(`(?i)\b(https?://[^\s]+|www\.[^\s]+|[a-z0-9-]+(\.[a-z0-9-]+)*\.(com|net|org|edu|gov|io|co|us|uk|ca|au|tv|me|ly)\b(/[^\s]*)?)`)
+
+// twitterWeights are the ranges of code points that count as a single character on Twitter. Every
+// other code point, such as CJK characters, counts as two. Taken from the twitter-text v3 config.
+var twitterWeights = [][2]rune{
+	{0, 4351},
+	{8192, 8205},
+	{8208, 8223},
+	{8242, 8247},
+}
+
+// TwitterLength returns the weighted length of s as counted by twitter-text. The text is NFC
+// normalised first, URLs count as `TransformedURLLength`, each emoji sequence counts as two, and
+// any code point outside of the Latin, punctuation and common symbol ranges counts as two.
+func TwitterLength(s string) int {
+	s = norm.NFC.String(s)
+	weighted := 0
+
+	forEachSegment(s, func(segment string, url bool) {
+		if url {
+			weighted += TransformedURLLength * 100
+			return
+		}
+
+		for len(segment) > 0 {
+			r, size := utf8.DecodeRuneInString(segment)
+
+			if isEmoji(r) {
+				size = emojiSequenceLength(segment)
+				weighted += 200
+			} else {
+				weighted += twitterWeight(r)
+			}
+
+			segment = segment[size:]
+		}
+	})
+
+	return weighted / 100
+}
+
+// MastodonLength returns the length of s as counted by Mastodon, which counts grapheme clusters
+// with URLs counting as `TransformedURLLength`.
+func MastodonLength(s string) int {
+	s = norm.NFC.String(s)
+	length := 0
+
+	forEachSegment(s, func(segment string, url bool) {
+		if url {
+			length += TransformedURLLength
+			return
+		}
+
+		length += GraphemeLength(segment)
+	})
+
+	return length
+}
+
+// forEachSegment splits s into URLs and the text between them.
+func forEachSegment(s string, fn func(segment string, url bool)) {
+	last := 0
+
+	for _, loc := range urlRegexp.FindAllStringIndex(s, -1) {
+		fn(s[last:loc[0]], false)
+		fn(s[loc[0]:loc[1]], true)
+		last = loc[1]
+	}
+
+	fn(s[last:], false)
+}
+
+func twitterWeight(r rune) int {
+	for _, weight := range twitterWeights {
+		if r >= weight[0] && r <= weight[1] {
+			return 100
+		}
+	}
+
+	return 200
+}
+
+// isEmoji reports whether r begins an emoji. This covers the emoji blocks and the symbols that
+// are presented as emoji, rather than the full Unicode emoji data.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	case r >= 0x2600 && r <= 0x27BF:
+		return true
+	case r >= 0x2300 && r <= 0x23FF:
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF:
+		return true
+	}
+
+	return false
+}
+
+// emojiSequenceLength returns the number of bytes of the emoji sequence at the start of s, which
+// includes any modifiers, variation selectors and zero width joined emoji.
+func emojiSequenceLength(s string) int {
+	r, size := utf8.DecodeRuneInString(s)
+	regional := isRegionalIndicator(r)
+	joined := false
+
+	for size < len(s) {
+		next, n := utf8.DecodeRuneInString(s[size:])
+
+		switch {
+		case joined && isEmoji(next):
+		case next == '\u20E3' || isGraphemeExtend(next):
+		case regional && isRegionalIndicator(next):
+			regional = false
+		default:
+			return size
+		}
+
+		joined = next == '\u200D'
+		size += n
+	}
+
+	return size
+}
@@ -0,0 +1,65 @@
+package forensicfilescorpus
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTwitterLength(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"It was murder.", 14},
+		{"José said “no”", 14},
+		{"See https://example.com/a/very/long/path/to/a/page for more", 36},
+		{"Visit www.example.org", 29},
+		{"犯罪", 4},
+		{"Lab \U0001F52C", 6},
+		{"\U0001F469\u200d\U0001F52C", 2},
+		{"\U0001F44D\U0001F3FD", 2},
+		{"\U0001F1EC\U0001F1E7", 2},
+		{strings.Repeat("a", 280), 280},
+	}
+
+	for _, test := range tests {
+		if got := TwitterLength(test.in); got != test.want {
+			t.Errorf("TwitterLength(%q) = %d, want %d", test.in, got, test.want)
+		}
+	}
+}
+
+func TestMastodonLength(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"It was murder.", 14},
+		{"犯罪", 2},
+		{"See https://example.com/a/very/long/path for more", 36},
+		{"\U0001F469\u200d\U0001F52C lab", 5},
+	}
+
+	for _, test := range tests {
+		if got := MastodonLength(test.in); got != test.want {
+			t.Errorf("MastodonLength(%q) = %d, want %d", test.in, got, test.want)
+		}
+	}
+}
+
+func TestPlatformLimits(t *testing.T) {
+	corpus := NewCorpus([]string{strings.Repeat("犯", 141) + ".", strings.Repeat("a", 200) + "."}, WithUnit(Twitter.Unit))
+
+	for i := 0; i < 20; i++ {
+		picked, err := corpus.Pick(-1, Twitter.Limit+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if TwitterLength(picked) > Twitter.Limit {
+			t.Fatalf("Pick() = sentence of weighted length %d, want at most %d", TwitterLength(picked), Twitter.Limit)
+		}
+	}
+}
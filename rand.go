@@ -0,0 +1,66 @@
+package forensicfilescorpus
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// WithSource sets the source of randomness used by the corpus. The source is guarded by a mutex, so
+// the corpus remains safe for concurrent use. Using a source created with a known seed, such as
+// `rand.NewSource(42)`, makes every pick from the corpus reproducible.
+func WithSource(src rand.Source) Option {
+	return func(c *Corpus) {
+		c.rng = rand.New(&lockedSource{src: src})
+	}
+}
+
+// WithRand sets the random number generator used by the corpus. A `*rand.Rand` is not safe for
+// concurrent use, so a corpus created with this option must not be shared between goroutines
+// unless r is. Use `WithSource` to share a corpus.
+func WithRand(r *rand.Rand) Option {
+	return func(c *Corpus) {
+		c.rng = r
+	}
+}
+
+// intn returns a random number in [0, n) using the corpus random number generator, or the default
+// source if the corpus was not given one.
+func (c *Corpus) intn(n int) int {
+	return intn(c.rng, n)
+}
+
+// intn returns a random number in [0, n) using rng, or the default source if rng is nil.
+func intn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+
+	return rng.Intn(n)
+}
+
+// lockedSource guards a source with a mutex so it can be used by multiple goroutines.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// randFloat returns a random number in [0, 1) using rng, or the default source if rng is nil.
+func randFloat(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.Float64()
+	}
+
+	return rng.Float64()
+}
@@ -0,0 +1,59 @@
+package forensicfilescorpus
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestSeededPicksAreReproducible(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func() []Option
+	}{
+		{"source", func() []Option { return []Option{WithSource(rand.NewSource(42))} }},
+		{"rand", func() []Option { return []Option{WithRand(rand.New(rand.NewSource(42)))} }},
+	}
+
+	for _, test := range tests {
+		a := NewCorpus(testSentences, test.options()...)
+		b := NewCorpus(testSentences, test.options()...)
+
+		for i := 0; i < 20; i++ {
+			x, err := a.Generate(40, 120)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			y, err := b.Generate(40, 120)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if x != y {
+				t.Fatalf("%s: Generate() = %q and %q with the same seed", test.name, x, y)
+			}
+		}
+	}
+}
+
+func TestSeededSourceIsSafeForConcurrentUse(t *testing.T) {
+	corpus := NewCorpus(testSentences, WithSource(rand.NewSource(1)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if _, err := corpus.Pick(-1, -1); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
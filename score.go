@@ -0,0 +1,303 @@
+package forensicfilescorpus
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scorer rates how good a sentence is to post, so that better sentences can be picked more often.
+// Scores must not be negative, and a sentence with a score of zero is never picked. See
+// `WithScorer`.
+type Scorer interface {
+	Score(sentence Sentence) float64
+}
+
+// ScorerFunc is a function used as a `Scorer`.
+type ScorerFunc func(sentence Sentence) float64
+
+// Score calls f.
+func (f ScorerFunc) Score(sentence Sentence) float64 {
+	return f(sentence)
+}
+
+// ForensicKeywords are words that make a sentence more likely to be about the forensics the show is
+// known for, and are favoured by `HeuristicScorer`.
+var ForensicKeywords = stringSet(`autopsy ballistics blood bloodstain bullet bullets coroner crime
+detective detectives dna evidence fiber fibers fingerprint fingerprints forensic forensics hair
+investigators lab laboratory luminol microscope murder pathologist samples scientist scientists
+suspect testing tests toxicology victim weapon`)
+
+// HeuristicScorer scores sentences without any manual input. Sentences score higher for having a
+// length within the ideal range, for using rare words and for using `ForensicKeywords`, and score
+// lower for being fragments: very short, missing end punctuation, or starting in lowercase.
+type HeuristicScorer struct {
+	// Vocabulary is used to find rare words. Without a vocabulary rare words are not favoured.
+	Vocabulary Vocabulary
+
+	// IdealMin and IdealMax are the ideal number of words for a sentence. Sentences outside of
+	// this range have their score reduced in proportion to how far outside they are.
+	IdealMin int
+	IdealMax int
+
+	// FragmentWords is the number of words a sentence must have to not be a fragment.
+	FragmentWords int
+}
+
+// NewHeuristicScorer creates a heuristic scorer with sensible defaults, using vocabulary to find
+// rare words. See `BuildVocabulary`.
+func NewHeuristicScorer(vocabulary Vocabulary) *HeuristicScorer {
+	return &HeuristicScorer{
+		Vocabulary:    vocabulary,
+		IdealMin:      8,
+		IdealMax:      25,
+		FragmentWords: 5,
+	}
+}
+
+// Score rates sentence using the heuristics.
+func (s *HeuristicScorer) Score(sentence Sentence) float64 {
+	tokens := Tokenise(sentence.Text)
+	words := len(tokens)
+
+	if words == 0 {
+		return 0
+	}
+
+	rarity := 0.0
+	content := 0
+	keywords := 0
+
+	for _, token := range tokens {
+		if Stopwords[token.Text] {
+			continue
+		}
+
+		content++
+
+		if s.Vocabulary != nil {
+			rarity += 1 / math.Log2(2+float64(s.Vocabulary[token.Text]))
+		}
+
+		if ForensicKeywords[token.Text] && keywords < 2 {
+			keywords++
+		}
+	}
+
+	if content > 0 {
+		rarity /= float64(content)
+	}
+
+	score := 1 + rarity + 0.5*float64(keywords)
+
+	switch {
+	case words < s.IdealMin:
+		score *= float64(words) / float64(s.IdealMin)
+	case words > s.IdealMax:
+		score *= float64(s.IdealMax) / float64(words)
+	}
+
+	if words < s.FragmentWords {
+		score *= 0.2
+	}
+
+	if sentence.End == "" {
+		score *= 0.5
+	}
+
+	if r, _ := utf8.DecodeRuneInString(sentence.Text); unicode.IsLower(r) {
+		score *= 0.5
+	}
+
+	return score
+}
+
+// RatingScorer scores sentences using manual ratings, keyed by `SentenceID`. Sentences without a
+// rating are scored by Fallback, or given a score of 1 when there is no fallback.
+type RatingScorer struct {
+	Ratings  map[string]float64
+	Fallback Scorer
+}
+
+// Score returns the rating of sentence.
+func (s *RatingScorer) Score(sentence Sentence) float64 {
+	if rating, ok := s.Ratings[SentenceID(sentence.Text)]; ok {
+		return rating
+	}
+
+	if s.Fallback != nil {
+		return s.Fallback.Score(sentence)
+	}
+
+	return 1
+}
+
+// ReadRatings reads manual ratings from r, where each line is a rating followed by a tab and the
+// sentence it rates, such as the output of `ffcorpus score`. Blank lines and lines starting with
+// "#" are ignored.
+func ReadRatings(r io.Reader) (*RatingScorer, error) {
+	scorer := &RatingScorer{Ratings: map[string]float64{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(text, "\t", 2)
+		if len(fields) != 2 {
+			return nil, errors.New("ratings line " + strconv.Itoa(line) + " must be a rating, a tab and a sentence")
+		}
+
+		rating, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil || rating < 0 {
+			return nil, errors.New("ratings line " + strconv.Itoa(line) + " must start with a rating of zero or more")
+		}
+
+		scorer.Ratings[SentenceID(fields[1])] = rating
+	}
+
+	return scorer, scanner.Err()
+}
+
+// ReadRatingsFile reads manual ratings from the file provided by path parameter. See `ReadRatings`.
+func ReadRatingsFile(path string) (*RatingScorer, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	return ReadRatings(src)
+}
+
+// NewScorer creates a `HeuristicScorer` using the vocabulary of corpus when ratings is empty or
+// "heuristic". Otherwise ratings is the location of manual ratings, see `ReadRatingsFile`, which
+// fall back to the heuristic scores for unrated sentences.
+func NewScorer(corpus *Corpus, ratings string) (Scorer, error) {
+	texts := make([]string, len(corpus.sentences))
+	for i, sentence := range corpus.sentences {
+		texts[i] = sentence.Text
+	}
+
+	heuristic := NewHeuristicScorer(BuildVocabulary(texts))
+	if ratings == "" || ratings == "heuristic" {
+		return heuristic, nil
+	}
+
+	scorer, err := ReadRatingsFile(ratings)
+	if err != nil {
+		return nil, err
+	}
+
+	scorer.Fallback = heuristic
+	return scorer, nil
+}
+
+// WithScorer weights picks from the corpus by the score of each sentence, so a sentence with twice
+// the score is picked twice as often. This applies to `Generate` too, as it picks each of its
+// sentences, and to a `ShuffleBag`, which uses better sentences earlier in each round. Scores are
+// computed the first time the corpus is picked from. To weight a corpus that has already been
+// loaded, such as with a scorer from `NewScorer`, use `Corpus.WithScorer`.
+func WithScorer(scorer Scorer) Option {
+	return func(c *Corpus) {
+		c.scorer = scorer
+	}
+}
+
+// WithScorer returns a copy of the corpus with picks weighted by scorer, in the same way as the
+// `WithScorer` option. The copy shares the sentences and length index of the corpus, so the
+// sentences are not measured again.
+func (c *Corpus) WithScorer(scorer Scorer) *Corpus {
+	d := &Corpus{corpusData: c.corpusData}
+	d.scorer = scorer
+
+	return d
+}
+
+// weightedTries is the number of times a weighted pick is drawn from the whole corpus before
+// falling back to only drawing from the sentences within the length range.
+const weightedTries = 32
+
+// buildWeights scores every sentence, and creates a sampler over the sentences in length order.
+func (c *Corpus) buildWeights() {
+	weights := make([]float64, len(c.byLength))
+	for n, i := range c.byLength {
+		weights[n] = math.Max(0, c.scorer.Score(c.sentences[i]))
+	}
+
+	c.weights = weights
+	c.alias, c.aliasErr = NewAliasSampler(weights)
+}
+
+// pickWeighted picks a sentence from byLength[lo:hi] in proportion to its score. Sentences are drawn
+// from the whole corpus until one within the range is found, which is quick when the range holds
+// most of the corpus. Otherwise a sampler is built for just the sentences within the range.
+func (c *Corpus) pickWeighted(lo, hi int) (int, error) {
+	c.weightsOnce.Do(c.buildWeights)
+
+	if c.aliasErr != nil {
+		return -1, c.aliasErr
+	}
+
+	for try := 0; try < weightedTries; try++ {
+		n := c.alias.Sample(c.rng)
+
+		if n >= lo && n < hi && (c.filter == nil || c.filter(c.sentences[c.byLength[n]])) {
+			return c.byLength[n], nil
+		}
+	}
+
+	weights := make([]float64, hi-lo)
+	for n := lo; n < hi; n++ {
+		if c.filter == nil || c.filter(c.sentences[c.byLength[n]]) {
+			weights[n-lo] = c.weights[n]
+		}
+	}
+
+	sampler, err := NewAliasSampler(weights)
+	if err != nil && c.filter != nil {
+		return -1, errNoMatch
+	}
+
+	if err != nil {
+		return -1, errors.New("no candidates with a positive score within the length range")
+	}
+
+	return c.byLength[lo+sampler.Sample(c.rng)], nil
+}
+
+// weightedShuffle orders ids so that each is placed ahead of the rest in proportion to its score,
+// using the keys of Efraimidis and Spirakis. The ids must be of sentences within the corpus.
+func (c *Corpus) weightedShuffle(ids []string, sentences map[string]int) {
+	keys := make(map[string]float64, len(ids))
+
+	for _, id := range ids {
+		weight := c.scorer.Score(c.sentences[sentences[id]])
+
+		if weight <= 0 {
+			keys[id] = math.Inf(-1)
+			continue
+		}
+
+		keys[id] = math.Log(randFloat(c.rng)) / weight
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return keys[ids[i]] > keys[ids[j]]
+	})
+}
@@ -0,0 +1,217 @@
+package forensicfilescorpus
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHeuristicScorer(t *testing.T) {
+	scorer := NewHeuristicScorer(nil)
+	base := "The detective drove across town to meet the family that morning."
+
+	tests := []struct {
+		name  string
+		text  string
+		worse bool
+	}{
+		{"fragment", "Across town.", true},
+		{"missing end punctuation", "The detective drove across town to meet the family that morning", true},
+		{"starting in lowercase", "the detective drove across town to meet the family that morning.", true},
+		{"too long", strings.Repeat("The detective drove across town and ", 6) + "stopped.", true},
+		{"forensic keywords", "The detective found fibers and blood across town that morning.", false},
+	}
+
+	want := scorer.Score(Sentence{Text: base, End: "."})
+
+	for _, test := range tests {
+		sentence := Sentence{Text: test.text, End: endPunctuation(test.text)}
+		got := scorer.Score(sentence)
+
+		if test.worse && got >= want || !test.worse && got <= want {
+			t.Errorf("%s: Score(%q) = %v, want %s than %v", test.name, test.text, got,
+				map[bool]string{true: "less", false: "more"}[test.worse], want)
+		}
+	}
+
+	if got := scorer.Score(Sentence{Text: "..."}); got != 0 {
+		t.Errorf("Score(\"...\") = %v, want 0 for a sentence without words", got)
+	}
+}
+
+func TestHeuristicScorerFavoursRareWords(t *testing.T) {
+	vocabulary := Vocabulary{"car": 1000, "drove": 50, "carpet": 1, "fibres": 1}
+	scorer := NewHeuristicScorer(vocabulary)
+
+	common := scorer.Score(Sentence{Text: "The car drove to the car park and the car left.", End: "."})
+	rare := scorer.Score(Sentence{Text: "The carpet fibres drove to the carpet park and left.", End: "."})
+
+	if rare <= common {
+		t.Errorf("Score() of rare words = %v, want more than %v for common words", rare, common)
+	}
+}
+
+func TestReadRatings(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{
+			name:  "ratings",
+			input: "# ratings\n2.5\tHe ran away.\n\n0\tIs this the man?\n",
+			want:  map[string]float64{"He ran away.": 2.5, "Is this the man?": 0},
+		},
+		{name: "missing tab", input: "2.5 He ran away.\n", wantErr: true},
+		{name: "not a number", input: "good\tHe ran away.\n", wantErr: true},
+		{name: "negative", input: "-1\tHe ran away.\n", wantErr: true},
+	}
+
+	for _, test := range tests {
+		scorer, err := ReadRatings(strings.NewReader(test.input))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: ReadRatings() error = %v, want error %v", test.name, err, test.wantErr)
+			continue
+		}
+
+		if test.wantErr {
+			continue
+		}
+
+		if len(scorer.Ratings) != len(test.want) {
+			t.Errorf("%s: ReadRatings() = %d ratings, want %d", test.name, len(scorer.Ratings), len(test.want))
+		}
+
+		for text, rating := range test.want {
+			if got := scorer.Score(Sentence{Text: text}); got != rating {
+				t.Errorf("%s: Score(%q) = %v, want %v", test.name, text, got, rating)
+			}
+		}
+
+		if got := scorer.Score(Sentence{Text: "Unrated."}); got != 1 {
+			t.Errorf("%s: Score() of an unrated sentence = %v, want 1", test.name, got)
+		}
+	}
+}
+
+func TestNewScorer(t *testing.T) {
+	dir := t.TempDir()
+	ratings := filepath.Join(dir, "ratings.tsv")
+	if err := ioutil.WriteFile(ratings, []byte("5\tHe ran away.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	corpus := NewCorpus(testSentences)
+
+	for _, spec := range []string{"", "heuristic"} {
+		scorer, err := NewScorer(corpus, spec)
+		if err != nil {
+			t.Fatalf("NewScorer(%q) error = %v", spec, err)
+		}
+
+		if _, ok := scorer.(*HeuristicScorer); !ok {
+			t.Errorf("NewScorer(%q) = %T, want a *HeuristicScorer", spec, scorer)
+		}
+	}
+
+	scorer, err := NewScorer(corpus, ratings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := scorer.Score(Sentence{Text: "He ran away."}); got != 5 {
+		t.Errorf("NewScorer(ratings).Score() of a rated sentence = %v, want 5", got)
+	}
+
+	unrated := corpus.sentences[4]
+	want := NewHeuristicScorer(BuildVocabulary(testSentences)).Score(unrated)
+	if got := scorer.Score(unrated); got != want {
+		t.Errorf("NewScorer(ratings).Score() of an unrated sentence = %v, want the heuristic %v", got, want)
+	}
+
+	if _, err := NewScorer(corpus, filepath.Join(dir, "missing.tsv")); err == nil {
+		t.Errorf("NewScorer() with missing ratings error = nil, want an error")
+	}
+}
+
+func TestCorpusWithScorer(t *testing.T) {
+	scores := map[string]float64{"He ran away.": 9, "Is this the man?": 1}
+	scorer := ScorerFunc(func(sentence Sentence) float64 { return scores[sentence.Text] })
+
+	loaded := NewCorpus(testSentences, WithSource(rand.NewSource(1)))
+	corpus := loaded.WithScorer(scorer)
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		sentence, err := corpus.Pick(-1, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts[sentence]++
+	}
+
+	if len(counts) != 2 || counts["He ran away."] < 8500 || counts["He ran away."] > 9500 {
+		t.Errorf("WithScorer() picks = %v, want about 9 in 10 of %q and no unscored sentences", counts, "He ran away.")
+	}
+
+	// The loaded corpus is left unweighted.
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		sentence, err := loaded.Pick(-1, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen[sentence] = true
+	}
+
+	if len(seen) != len(testSentences) {
+		t.Errorf("Pick() after WithScorer() = %d sentences, want every one of %d", len(seen), len(testSentences))
+	}
+
+	// Only the zero scored sentences are within this range.
+	if _, err := corpus.Pick(20, -1); err == nil {
+		t.Errorf("WithScorer() Pick(20, -1) error = nil, want an error as no sentence has a score")
+	}
+
+	// Everything else the corpus was created with is kept.
+	filtered := NewCorpus(testSentences, WithUnit(Bytes), WithFilter(EndsWith("!"))).WithScorer(scorer)
+	if _, err := filtered.Pick(-1, -1); err != errNoMatch {
+		t.Errorf("WithScorer() with a filter Pick() error = %v, want %v", err, errNoMatch)
+	}
+
+	if filtered.Unit().Name != Bytes.Name {
+		t.Errorf("WithScorer().Unit() = %s, want %s", filtered.Unit().Name, Bytes.Name)
+	}
+}
+
+func TestWeightedShuffle(t *testing.T) {
+	scores := map[string]float64{"a": 9, "b": 1, "c": 0}
+	scorer := ScorerFunc(func(sentence Sentence) float64 { return scores[sentence.Text] })
+	corpus := NewCorpus([]string{"a", "b", "c"}, WithSource(rand.NewSource(1)), WithScorer(scorer))
+
+	sentences := map[string]int{"a": 0, "b": 1, "c": 2}
+	first := 0
+
+	for i := 0; i < 10000; i++ {
+		ids := []string{"c", "b", "a"}
+		corpus.weightedShuffle(ids, sentences)
+
+		if ids[2] != "c" {
+			t.Fatalf("weightedShuffle() = %v, want the zero scored id last", ids)
+		}
+
+		if ids[0] == "a" {
+			first++
+		}
+	}
+
+	// "a" is placed first with probability 9 / (9 + 1).
+	if first < 8800 || first > 9200 {
+		t.Errorf("weightedShuffle() placed the best id first %d times in 10000, want about 9000", first)
+	}
+}
@@ -0,0 +1,454 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	// BM25K1 controls how quickly repeated occurrences of a term stop adding to the score of a
+	// sentence within search results.
+	BM25K1 = 1.2
+
+	// BM25B controls how much the score of a sentence within search results is reduced for being
+	// longer than average.
+	BM25B = 0.75
+)
+
+// SearchResult is a sentence matching a search query, along with its index within the corpus, its
+// BM25 score and the spans of the sentence text that matched the query.
+type SearchResult struct {
+	Sentence Sentence
+	Index    int
+	Score    float64
+	Matches  []Span
+}
+
+// Span is a range of bytes within the text of a sentence, from Start up to but not including End.
+type Span struct {
+	Start int
+	End   int
+}
+
+// WithStemmer sets the stemmer used to index and search the corpus, so that different forms of a
+// word match each other. By default words are only lowercased. See `Stem`.
+func WithStemmer(stem func(string) string) Option {
+	return func(c *Corpus) {
+		c.stem = stem
+	}
+}
+
+// invertedIndex maps each term within a corpus to the sentences it appears in, along with the
+// position of each occurrence so that phrases can be matched.
+type invertedIndex struct {
+	postings map[string][]posting
+	terms    []string
+	lengths  []int
+	average  float64
+}
+
+type posting struct {
+	sentence  int
+	positions []int
+}
+
+// queryTerm is a word, a prefix or a phrase within a search query. A prefix only has one word.
+type queryTerm struct {
+	words  []string
+	prefix bool
+}
+
+// clause is a set of terms that must all appear in a sentence, and terms that must not.
+type clause struct {
+	include []queryTerm
+	exclude []queryTerm
+}
+
+// Search finds the sentences within the corpus matching query, ranked using BM25. A query is made
+// of words which must all appear in a sentence, in any form when the corpus has a stemmer:
+//   - "quoted words" must appear together as a phrase.
+//   - lumin* matches any word starting with "lumin".
+//   - -word or NOT word excludes sentences containing the word.
+//   - a OR b matches sentences matching either side. OR binds more loosely than the implicit AND,
+//     and parentheses are not supported.
+//
+// Up to limit results are returned, or every result when limit is not positive. The index is built
+// the first time the corpus is searched.
+func (c *Corpus) Search(query string, limit int) ([]SearchResult, error) {
+	clauses, err := c.parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.indexOnce.Do(c.buildIndex)
+
+	matched := map[int]bool{}
+	var terms []queryTerm
+
+	for _, clause := range clauses {
+		for sentence := range c.index.matchClause(clause) {
+			matched[sentence] = true
+		}
+
+		terms = append(terms, clause.include...)
+	}
+
+	found := make([]map[int][]int, len(terms))
+	for i, term := range terms {
+		found[i] = c.index.match(term)
+	}
+
+	var results []SearchResult
+	for sentence := range matched {
+		results = append(results, c.index.result(c, sentence, terms, found))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Index < results[j].Index
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// term returns the indexed form of a lowercased word.
+func (c *Corpus) term(word string) string {
+	if c.stem == nil {
+		return word
+	}
+
+	return c.stem(word)
+}
+
+func (c *Corpus) buildIndex() {
+	index := &invertedIndex{
+		postings: map[string][]posting{},
+		lengths:  make([]int, len(c.sentences)),
+	}
+
+	total := 0
+
+	for i, sentence := range c.sentences {
+		positions := map[string][]int{}
+		tokens := Tokenise(sentence.Text)
+
+		for n, token := range tokens {
+			term := c.term(token.Text)
+			positions[term] = append(positions[term], n)
+		}
+
+		for term, p := range positions {
+			index.postings[term] = append(index.postings[term], posting{i, p})
+		}
+
+		index.lengths[i] = len(tokens)
+		total += len(tokens)
+	}
+
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+
+	if len(c.sentences) > 0 {
+		index.average = float64(total) / float64(len(c.sentences))
+	}
+
+	c.index = index
+}
+
+// parseQuery splits query into clauses, any of which may match a sentence.
+func (c *Corpus) parseQuery(query string) ([]clause, error) {
+	var clauses []clause
+	current := clause{}
+	exclude := false
+
+	for _, field := range queryFields(query) {
+		switch {
+		case field == "OR":
+			clauses = append(clauses, current)
+			current = clause{}
+			continue
+		case field == "AND":
+			continue
+		case field == "NOT":
+			exclude = true
+			continue
+		case strings.HasPrefix(field, "-") && len(field) > 1:
+			exclude = true
+			field = field[1:]
+		}
+
+		term, ok := c.queryTerm(field)
+		if !ok {
+			exclude = false
+			continue
+		}
+
+		if exclude {
+			current.exclude = append(current.exclude, term)
+		} else {
+			current.include = append(current.include, term)
+		}
+
+		exclude = false
+	}
+
+	clauses = append(clauses, current)
+
+	for _, clause := range clauses {
+		if len(clause.include) == 0 {
+			return nil, errors.New("search query must include at least one word to match, and on each side of OR")
+		}
+	}
+
+	return clauses, nil
+}
+
+// queryTerm creates a term from a field of a query, which is a phrase when the field was quoted or
+// contains more than one word, such as "DNA-testing".
+func (c *Corpus) queryTerm(field string) (queryTerm, bool) {
+	field = strings.Trim(field, `"`)
+	prefix := strings.HasSuffix(field, "*")
+
+	tokens := Tokenise(field)
+	if len(tokens) == 0 {
+		return queryTerm{}, false
+	}
+
+	var term queryTerm
+	for _, token := range tokens {
+		term.words = append(term.words, token.Text)
+	}
+
+	if prefix && len(tokens) == 1 {
+		term.prefix = true
+		return term, true
+	}
+
+	for i, word := range term.words {
+		term.words[i] = c.term(word)
+	}
+
+	return term, true
+}
+
+// queryFields splits query on whitespace, keeping quoted phrases together with their quotes.
+func queryFields(query string) []string {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			field.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+
+	return fields
+}
+
+// matchClause returns the sentences matching every included term of clause and none of the
+// excluded terms.
+func (index *invertedIndex) matchClause(clause clause) map[int]bool {
+	var matched map[int]bool
+
+	for _, term := range clause.include {
+		found := index.match(term)
+
+		if matched == nil {
+			matched = map[int]bool{}
+			for sentence := range found {
+				matched[sentence] = true
+			}
+
+			continue
+		}
+
+		for sentence := range matched {
+			if _, ok := found[sentence]; !ok {
+				delete(matched, sentence)
+			}
+		}
+	}
+
+	for _, term := range clause.exclude {
+		for sentence := range index.match(term) {
+			delete(matched, sentence)
+		}
+	}
+
+	return matched
+}
+
+// match returns the positions where term starts within each sentence it appears in.
+func (index *invertedIndex) match(term queryTerm) map[int][]int {
+	found := map[int][]int{}
+
+	if term.prefix {
+		i := sort.SearchStrings(index.terms, term.words[0])
+		for ; i < len(index.terms) && strings.HasPrefix(index.terms[i], term.words[0]); i++ {
+			for _, p := range index.postings[index.terms[i]] {
+				found[p.sentence] = append(found[p.sentence], p.positions...)
+			}
+		}
+
+		return found
+	}
+
+	for _, p := range index.postings[term.words[0]] {
+		found[p.sentence] = p.positions
+	}
+
+	for offset, word := range term.words[1:] {
+		next := map[int]map[int]bool{}
+		for _, p := range index.postings[word] {
+			next[p.sentence] = map[int]bool{}
+			for _, position := range p.positions {
+				next[p.sentence][position] = true
+			}
+		}
+
+		for sentence, starts := range found {
+			var kept []int
+			for _, start := range starts {
+				if next[sentence][start+offset+1] {
+					kept = append(kept, start)
+				}
+			}
+
+			if len(kept) == 0 {
+				delete(found, sentence)
+			} else {
+				found[sentence] = kept
+			}
+		}
+	}
+
+	return found
+}
+
+// result scores a matched sentence using BM25 over terms, and finds the spans of text they match.
+// The sentences each term was found in are given by found, in the same order as terms.
+func (index *invertedIndex) result(c *Corpus, sentence int, terms []queryTerm, found []map[int][]int) SearchResult {
+	result := SearchResult{Sentence: c.sentences[sentence], Index: sentence}
+	tokens := Tokenise(result.Sentence.Text)
+	length := float64(index.lengths[sentence])
+	highlighted := map[int]bool{}
+
+	for t, term := range terms {
+		positions := found[t][sentence]
+
+		if len(positions) == 0 {
+			continue
+		}
+
+		n := float64(len(found[t]))
+		idf := math.Log(1 + (float64(len(c.sentences))-n+0.5)/(n+0.5))
+		tf := float64(len(positions))
+		result.Score += idf * tf * (BM25K1 + 1) / (tf + BM25K1*(1-BM25B+BM25B*length/index.average))
+
+		for _, start := range positions {
+			end := start + len(term.words) - 1
+			for i := start; i <= end; i++ {
+				highlighted[i] = true
+			}
+		}
+	}
+
+	for i, token := range tokens {
+		if !highlighted[i] {
+			continue
+		}
+
+		last := len(result.Matches) - 1
+		if last >= 0 && highlighted[i-1] {
+			result.Matches[last].End = token.End
+			continue
+		}
+
+		result.Matches = append(result.Matches, Span{token.Start, token.End})
+	}
+
+	return result
+}
+
+// Highlight wraps each span of text with before and after, such as "[" and "]". The spans must be
+// in order and must not overlap, as they are within a `SearchResult`.
+func Highlight(text string, spans []Span, before, after string) string {
+	var out strings.Builder
+	last := 0
+
+	for _, span := range spans {
+		out.WriteString(text[last:span.Start])
+		out.WriteString(before)
+		out.WriteString(text[span.Start:span.End])
+		out.WriteString(after)
+		last = span.End
+	}
+
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// Stem reduces an English word to a stem by removing common suffixes, so that "fibers" and "fiber",
+// or "stabbed" and "stabbing", share a stem. This is a simplification of the Porter stemmer, and the
+// stem is not always a real word. Pass it to `WithStemmer` to search using stems.
+func Stem(word string) string {
+	word = strings.TrimSuffix(word, "'s")
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && len(word) > 3 && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	}
+
+	for _, suffix := range []string{"ing", "ed", "ly"} {
+		stem := strings.TrimSuffix(word, suffix)
+
+		if stem != word && len(stem) >= 3 && strings.ContainsAny(stem, "aeiouy") {
+			return undouble(stem)
+		}
+	}
+
+	return word
+}
+
+// undouble removes the last letter of stem when it is a doubled consonant, such as "stabb" from
+// "stabbed", unless it is one that is usually doubled such as "kill".
+func undouble(stem string) string {
+	n := len(stem)
+
+	if stem[n-1] == stem[n-2] && !strings.ContainsAny(stem[n-1:], "aeiouylsz") {
+		return stem[:n-1]
+	}
+
+	return stem
+}
@@ -0,0 +1,208 @@
+package forensicfilescorpus
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var searchSentences = []string{
+	"The fibers matched a carpet in the van.",
+	"Luminol revealed blood on the carpet.",
+	"Investigators found fiber evidence.",
+	"The van was found in the river.",
+	"Fibers and blood were found.",
+}
+
+func searchIndexes(t *testing.T, corpus *Corpus, query string) []int {
+	t.Helper()
+
+	results, err := corpus.Search(query, 0)
+	if err != nil {
+		t.Fatalf("Search(%q) error = %v", query, err)
+	}
+
+	indexes := []int{}
+	for _, result := range results {
+		indexes = append(indexes, result.Index)
+	}
+
+	sort.Ints(indexes)
+	return indexes
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		stemmed bool
+		want    []int
+	}{
+		{"word", "carpet", false, []int{0, 1}},
+		{"case insensitive", "CARPET", false, []int{0, 1}},
+		{"unstemmed", "fibers", false, []int{0, 4}},
+		{"stemmed", "fibers", true, []int{0, 2, 4}},
+		{"implicit and", "blood found", false, []int{4}},
+		{"explicit and", "blood AND found", false, []int{4}},
+		{"phrase", `"the carpet"`, false, []int{1}},
+		{"phrase in order", `"carpet the"`, false, []int{}},
+		{"hyphenated phrase", "carpet-in", false, []int{0}},
+		{"prefix", "lumin*", false, []int{1}},
+		{"prefix of several words", "fib*", false, []int{0, 2, 4}},
+		{"exclude", "found -river", false, []int{2, 4}},
+		{"not", "found NOT river", false, []int{2, 4}},
+		{"or", "luminol OR river", false, []int{1, 3}},
+		{"or binds loosely", "carpet van OR river", false, []int{0, 3}},
+		{"no match", "dna", false, []int{}},
+	}
+
+	plain := NewCorpus(searchSentences)
+	stemmed := NewCorpus(searchSentences, WithStemmer(Stem))
+
+	for _, test := range tests {
+		corpus := plain
+		if test.stemmed {
+			corpus = stemmed
+		}
+
+		if got := searchIndexes(t, corpus, test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Search(%q) = %v, want %v", test.name, test.query, got, test.want)
+		}
+	}
+}
+
+func TestSearchInvalidQuery(t *testing.T) {
+	corpus := NewCorpus(searchSentences)
+
+	for _, query := range []string{"", "  ", "-blood", "NOT blood", "carpet OR", "OR carpet", `"..."`} {
+		if _, err := corpus.Search(query, 0); err == nil {
+			t.Errorf("Search(%q) error = nil, want an error", query)
+		}
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	corpus := NewCorpus(searchSentences)
+
+	results, err := corpus.Search("van", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both sentences mention the van once, so the shorter sentence ranks first.
+	if len(results) != 2 || results[0].Index != 3 || results[1].Index != 0 {
+		t.Fatalf("Search(\"van\") = %+v, want sentences 3 then 0", results)
+	}
+
+	if results[0].Score <= results[1].Score || results[1].Score <= 0 {
+		t.Errorf("Search(\"van\") scores = %v, %v, want positive and decreasing", results[0].Score, results[1].Score)
+	}
+
+	limited, err := corpus.Search("found", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(limited) != 2 {
+		t.Errorf("Search(\"found\", 2) = %d results, want 2", len(limited))
+	}
+}
+
+func TestSearchMatches(t *testing.T) {
+	tests := []struct {
+		query string
+		index int
+		want  []Span
+	}{
+		{"carpet", 1, []Span{{30, 36}}},
+		{`"the carpet"`, 1, []Span{{26, 36}}},
+		{"luminol carpet", 1, []Span{{0, 7}, {30, 36}}},
+		{"blood carpet", 1, []Span{{17, 22}, {30, 36}}},
+		{"the van", 0, []Span{{0, 3}, {31, 38}}},
+		{"lumin*", 1, []Span{{0, 7}}},
+	}
+
+	corpus := NewCorpus(searchSentences)
+
+	for _, test := range tests {
+		results, err := corpus.Search(test.query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []Span
+		for _, result := range results {
+			if result.Index == test.index {
+				got = result.Matches
+			}
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Search(%q) sentence %d matches = %v, want %v", test.query, test.index, got, test.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		spans []Span
+		want  string
+	}{
+		{"He ran away.", nil, "He ran away."},
+		{"He ran away.", []Span{{3, 6}}, "He [ran] away."},
+		{"He ran away.", []Span{{0, 2}, {7, 11}}, "[He] ran [away]."},
+		{"He ran away.", []Span{{0, 12}}, "[He ran away.]"},
+	}
+
+	for _, test := range tests {
+		if got := Highlight(test.text, test.spans, "[", "]"); got != test.want {
+			t.Errorf("Highlight(%q, %v) = %q, want %q", test.text, test.spans, got, test.want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"fibers", "fiber"},
+		{"fiber", "fiber"},
+		{"bodies", "body"},
+		{"glasses", "glass"},
+		{"analysis", "analysis"},
+		{"bus", "bus"},
+		{"victim's", "victim"},
+		{"stabbed", "stab"},
+		{"stabbing", "stab"},
+		{"killed", "kill"},
+		{"quickly", "quick"},
+		{"red", "red"},
+		{"sing", "sing"},
+	}
+
+	for _, test := range tests {
+		if got := Stem(test.word); got != test.want {
+			t.Errorf("Stem(%q) = %q, want %q", test.word, got, test.want)
+		}
+	}
+}
+
+func TestQueryFields(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", nil},
+		{"DNA", []string{"DNA"}},
+		{`DNA  "river bank" -blood`, []string{"DNA", `"river bank"`, "-blood"}},
+		{`"unterminated phrase`, []string{`"unterminated phrase`}},
+	}
+
+	for _, test := range tests {
+		if got := queryFields(test.query); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("queryFields(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package forensicfilescorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SentenceID returns a stable identifier for a sentence, derived from its text, so that the same
+// sentence has the same identifier across corpus updates and compilations.
+func SentenceID(text string) string {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// BagState is the persisted state of a `ShuffleBag`: a permutation of sentence identifiers, and a
+// cursor pointing at the next sentence within the permutation to be used.
+type BagState struct {
+	Order  []string `json:"order"`
+	Cursor int      `json:"cursor"`
+}
+
+// BagStore persists the state of a `ShuffleBag` between runs.
+type BagStore interface {
+	// Load returns the saved state, or an empty state if nothing has been saved yet.
+	Load() (*BagState, error)
+
+	// Save replaces the saved state.
+	Save(state *BagState) error
+}
+
+// BagStoreFor returns the store for a location, chosen by the scheme of the location in the same
+// way as `SourceFor`. Paths and "file://" URLs are stored in a local file, see `FileBagStore`, and
+// "s3://bucket/key" URLs are stored as an object, see `S3BagStore`.
+func BagStoreFor(location string) (BagStore, error) {
+	if !strings.Contains(location, "://") {
+		return FileBagStore(location), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return FileBagStore(u.Path), nil
+	case "s3":
+		return S3BagStore{s3SourceFor(u)}, nil
+	}
+
+	return nil, errors.New("unsupported shuffle bag location, must be a path or an s3:// URL")
+}
+
+// FileBagStore stores the state of a shuffle bag as JSON within the file at the given path.
+type FileBagStore string
+
+// Load reads the state from the file, or returns an empty state if the file does not exist.
+func (s FileBagStore) Load() (*BagState, error) {
+	data, err := ioutil.ReadFile(string(s))
+	if os.IsNotExist(err) {
+		return &BagState{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalBagState(data)
+}
+
+// Save writes the state to a temporary file which then replaces the file, so that the state is
+// never left partly written.
+func (s FileBagStore) Save(state *BagState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(string(s)), filepath.Base(string(s))+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), string(s))
+}
+
+// S3BagStore stores the state of a shuffle bag as JSON within an object of an S3 compatible object
+// store, for when the bot runs somewhere without persistent local storage such as Lambda.
+type S3BagStore struct {
+	Object *S3Source
+}
+
+// Load reads the state from the object, or returns an empty state if the object does not exist.
+func (s S3BagStore) Load() (*BagState, error) {
+	data, err := s.Object.get()
+	if os.IsNotExist(err) {
+		return &BagState{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalBagState(data)
+}
+
+// Save replaces the object with the state.
+func (s S3BagStore) Save(state *BagState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return s.Object.put(data)
+}
+
+func unmarshalBagState(data []byte) (*BagState, error) {
+	state := &BagState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// ShuffleBag picks sentences from a corpus without repeats: every sentence is used once, in a
+// random order, before any sentence is used again. The order and how far through it the bag is are
+// kept in a `BagStore`, so the bag carries on where it left off each time the program runs.
+//
+// When the corpus changes the saved order is updated to match it. Sentences that were removed are
+// dropped, and new sentences are shuffled in with those not yet used, so they are used before the
+// bag starts again. Sentences are identified by `SentenceID`, so a sentence that is edited counts as
+// a new sentence.
+type ShuffleBag struct {
+	corpus *Corpus
+	store  BagStore
+
+	// ids holds the identifier of each distinct sentence in the order it appears within the
+	// corpus, and sentences maps each of those identifiers to the index of the sentence.
+	ids       []string
+	sentences map[string]int
+
+	// pending is the state after the last sentence returned by `Next`, which is saved by `Commit`.
+	pending *BagState
+}
+
+// NewShuffleBag creates a shuffle bag for the sentences within corpus, using the randomisation
+// source of the corpus to shuffle them.
+func NewShuffleBag(corpus *Corpus, store BagStore) *ShuffleBag {
+	b := &ShuffleBag{corpus: corpus, store: store, sentences: map[string]int{}}
+
+	for i, sentence := range corpus.sentences {
+		id := SentenceID(sentence.Text)
+
+		if _, ok := b.sentences[id]; !ok {
+			b.sentences[id] = i
+			b.ids = append(b.ids, id)
+		}
+	}
+
+	return b
+}
+
+// Next returns the next sentence within the bag that is accepted by filter. The state of the bag is
+// not saved until `Commit` is called, so a sentence that could not be used, such as a failed post,
+// is returned again the next time the bag is used. Sentences that are not accepted are left within
+// the bag for a later call, so the filter can change between calls, such as one rejecting
+// sentences like those posted recently. The bag starts again once no unused sentence is accepted.
+// A filter given to the corpus with `WithFilter` also applies.
+func (b *ShuffleBag) Next(filter Filter) (Sentence, error) {
+	filter = All(filter, b.corpus.filter)
+
+	if !b.accepts(filter) {
+		return Sentence{}, errors.New("no candidates with given filters")
+	}
+
+	state, err := b.store.Load()
+	if err != nil {
+		return Sentence{}, err
+	}
+
+	b.reconcile(state)
+
+	next := b.find(state, filter)
+	if next < 0 {
+		b.refill(state)
+		next = b.find(state, filter)
+	}
+
+	// The accepted sentence is swapped with the sentence at the cursor, so that sentences passed
+	// over remain after the cursor and unused.
+	state.Order[state.Cursor], state.Order[next] = state.Order[next], state.Order[state.Cursor]
+	sentence := b.corpus.sentences[b.sentences[state.Order[state.Cursor]]]
+	state.Cursor++
+
+	b.pending = state
+	return sentence, nil
+}
+
+// find returns the position of the first unused sentence within state accepted by filter, or -1
+// if there is none.
+func (b *ShuffleBag) find(state *BagState, filter Filter) int {
+	for i := state.Cursor; i < len(state.Order); i++ {
+		if filter(b.corpus.sentences[b.sentences[state.Order[i]]]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Commit saves the state of the bag after the sentence last returned by `Next`, marking it as
+// used. Call it once the sentence has been used successfully.
+func (b *ShuffleBag) Commit() error {
+	if b.pending == nil {
+		return errors.New("no sentence to commit, call Next first")
+	}
+
+	if err := b.store.Save(b.pending); err != nil {
+		return err
+	}
+
+	b.pending = nil
+	return nil
+}
+
+// accepts reports whether filter accepts any sentence, so that `Next` does not go round the bag
+// forever.
+func (b *ShuffleBag) accepts(filter Filter) bool {
+	for _, i := range b.sentences {
+		if filter(b.corpus.sentences[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reconcile updates state to match the corpus, dropping sentences that are no longer within the
+// corpus and shuffling new sentences in with the sentences not yet used.
+func (b *ShuffleBag) reconcile(state *BagState) {
+	order := make([]string, 0, len(b.ids))
+	cursor := state.Cursor
+	seen := map[string]bool{}
+
+	for i, id := range state.Order {
+		if _, ok := b.sentences[id]; !ok || seen[id] {
+			if i < state.Cursor {
+				cursor--
+			}
+
+			continue
+		}
+
+		seen[id] = true
+		order = append(order, id)
+	}
+
+	if cursor > len(order) {
+		cursor = len(order)
+	}
+
+	added := false
+	for _, id := range b.ids {
+		if !seen[id] {
+			order = append(order, id)
+			added = true
+		}
+	}
+
+	if added {
+		b.shuffle(order[cursor:])
+	}
+
+	state.Order = order
+	state.Cursor = cursor
+}
+
+// refill starts the bag again with a new order. The last sentence used is kept away from the start
+// of the new order, so the same sentence is never used twice in a row.
+func (b *ShuffleBag) refill(state *BagState) {
+	last := ""
+	if state.Cursor > 0 {
+		last = state.Order[state.Cursor-1]
+	}
+
+	b.shuffle(state.Order)
+
+	if len(state.Order) > 1 && state.Order[0] == last {
+		j := 1 + b.corpus.intn(len(state.Order)-1)
+		state.Order[0], state.Order[j] = state.Order[j], state.Order[0]
+	}
+
+	state.Cursor = 0
+}
+
+// shuffle orders ids at random, with better sentences towards the start when the corpus has a
+// scorer.
+func (b *ShuffleBag) shuffle(ids []string) {
+	if b.corpus.scorer != nil {
+		b.corpus.weightedShuffle(ids, b.sentences)
+		return
+	}
+
+	for i := len(ids) - 1; i > 0; i-- {
+		j := b.corpus.intn(i + 1)
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+}
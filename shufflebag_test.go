@@ -0,0 +1,315 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// memoryBagStore keeps the state of a shuffle bag in memory, optionally failing to save it.
+type memoryBagStore struct {
+	state *BagState
+	saves int
+	err   error
+}
+
+func (s *memoryBagStore) Load() (*BagState, error) {
+	if s.state == nil {
+		return &BagState{}, nil
+	}
+
+	state := &BagState{Order: append([]string(nil), s.state.Order...), Cursor: s.state.Cursor}
+	return state, nil
+}
+
+func (s *memoryBagStore) Save(state *BagState) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.saves++
+	s.state = &BagState{Order: append([]string(nil), state.Order...), Cursor: state.Cursor}
+	return nil
+}
+
+func nextCommitted(t *testing.T, bag *ShuffleBag, filter Filter) Sentence {
+	t.Helper()
+
+	sentence, err := bag.Next(filter)
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	if err := bag.Commit(); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	return sentence
+}
+
+func TestShuffleBagUsesEverySentenceOnce(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		corpus := NewCorpus(testSentences, WithSource(rand.NewSource(seed)))
+		store := &memoryBagStore{}
+
+		counts := map[string]int{}
+		previous := ""
+
+		for round := 1; round <= 3; round++ {
+			for i := 0; i < len(testSentences); i++ {
+				// A new bag each time, as when the bot runs, so only the store carries state.
+				sentence := nextCommitted(t, NewShuffleBag(corpus, store), nil)
+
+				if sentence.Text == previous {
+					t.Errorf("seed %d: Next() returned %q twice in a row", seed, sentence.Text)
+				}
+
+				previous = sentence.Text
+				counts[sentence.Text]++
+			}
+
+			for _, text := range testSentences {
+				if counts[text] != round {
+					t.Errorf("seed %d: after round %d %q was used %d times", seed, round, text, counts[text])
+				}
+			}
+		}
+	}
+}
+
+func TestShuffleBagCommit(t *testing.T) {
+	corpus := NewCorpus(testSentences, WithSource(rand.NewSource(1)))
+	store := &memoryBagStore{}
+	bag := NewShuffleBag(corpus, store)
+
+	if err := bag.Commit(); err == nil {
+		t.Errorf("Commit() before Next() error = nil, want an error")
+	}
+
+	nextCommitted(t, bag, nil)
+	saves := store.saves
+
+	first, err := bag.Next(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if store.saves != saves {
+		t.Errorf("Next() saved the bag, want it saved only by Commit")
+	}
+
+	// Without a commit, such as when posting failed, the same sentence is returned again.
+	again, err := NewShuffleBag(corpus, store).Next(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if again.Text != first.Text {
+		t.Errorf("Next() without Commit() = %q, want %q again", again.Text, first.Text)
+	}
+
+	if err := bag.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bag.Commit(); err == nil {
+		t.Errorf("Commit() twice error = nil, want an error")
+	}
+
+	next, err := NewShuffleBag(corpus, store).Next(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next.Text == first.Text {
+		t.Errorf("Next() after Commit() = %q, want a different sentence", next.Text)
+	}
+}
+
+func TestShuffleBagCommitSaveError(t *testing.T) {
+	failure := errors.New("store unavailable")
+	store := &memoryBagStore{err: failure}
+	bag := NewShuffleBag(NewCorpus(testSentences), store)
+
+	if _, err := bag.Next(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bag.Commit(); err != failure {
+		t.Errorf("Commit() error = %v, want %v", err, failure)
+	}
+
+	// The pending state is kept so that saving can be tried again.
+	store.err = nil
+
+	if err := bag.Commit(); err != nil {
+		t.Errorf("Commit() after a failed save error = %v", err)
+	}
+}
+
+func TestShuffleBagFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		filter  Filter
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "length",
+			filter: Between(-1, 20),
+			want:   []string{"He ran away.", "The van was found.", "Is this the man?"},
+		},
+		{
+			name:    "corpus filter",
+			options: []Option{WithFilter(EndsWith("?"))},
+			want:    []string{"Is this the man?"},
+		},
+		{
+			name:    "nothing accepted",
+			filter:  Between(100, -1),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		bag := NewShuffleBag(NewCorpus(testSentences, test.options...), &memoryBagStore{})
+
+		for i := 0; i < 2*len(testSentences); i++ {
+			sentence, err := bag.Next(test.filter)
+			if (err != nil) != test.wantErr {
+				t.Errorf("%s: Next() error = %v, want error %v", test.name, err, test.wantErr)
+				break
+			}
+
+			if err != nil {
+				break
+			}
+
+			if !containsString(test.want, sentence.Text) {
+				t.Errorf("%s: Next() = %q, want one of %q", test.name, sentence.Text, test.want)
+			}
+
+			if err := bag.Commit(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestShuffleBagDuplicateTexts(t *testing.T) {
+	corpus := NewCorpus([]string{"He ran away.", "He ran away.", "Is this the man?"})
+	bag := NewShuffleBag(corpus, &memoryBagStore{})
+
+	first := nextCommitted(t, bag, nil)
+	second := nextCommitted(t, bag, nil)
+
+	if first.Text == second.Text {
+		t.Errorf("Next() = %q twice within a round, want each distinct sentence once", first.Text)
+	}
+}
+
+func TestShuffleBagCorpusChanges(t *testing.T) {
+	store := &memoryBagStore{}
+	before := NewCorpus(testSentences[:3], WithSource(rand.NewSource(3)))
+
+	used := nextCommitted(t, NewShuffleBag(before, store), nil)
+
+	// One unused sentence is removed and two are added, so the rest of the round is every sentence
+	// still within the corpus apart from the one already used.
+	var removed string
+	var after []string
+	for _, text := range testSentences {
+		if removed == "" && text != used.Text {
+			removed = text
+			continue
+		}
+
+		after = append(after, text)
+	}
+
+	bag := NewShuffleBag(NewCorpus(after, WithSource(rand.NewSource(3))), store)
+
+	var rest []string
+	for i := 0; i < len(after)-1; i++ {
+		rest = append(rest, nextCommitted(t, bag, nil).Text)
+	}
+
+	for _, text := range after {
+		if (text == used.Text) == containsString(rest, text) {
+			t.Errorf("the rest of the round = %q, want every sentence but %q once", rest, used.Text)
+		}
+	}
+
+	if containsString(rest, removed) {
+		t.Errorf("the rest of the round = %q, which includes the removed %q", rest, removed)
+	}
+}
+
+func TestFileBagStore(t *testing.T) {
+	store := FileBagStore(filepath.Join(t.TempDir(), "bag.json"))
+
+	state, err := store.Load()
+	if err != nil || len(state.Order) != 0 || state.Cursor != 0 {
+		t.Errorf("Load() of a missing file = %+v, %v, want an empty state", state, err)
+	}
+
+	want := &BagState{Order: []string{"a", "b", "c"}, Cursor: 2}
+	if err := store.Save(want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBagStoreFor(t *testing.T) {
+	tests := []struct {
+		location string
+		want     BagStore
+		wantErr  bool
+	}{
+		{location: "bag.json", want: FileBagStore("bag.json")},
+		{location: "file:///tmp/bag.json", want: FileBagStore("/tmp/bag.json")},
+		{location: "https://example.com/bag.json", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := BagStoreFor(test.location)
+		if (err != nil) != test.wantErr {
+			t.Errorf("BagStoreFor(%q) error = %v, want error %v", test.location, err, test.wantErr)
+			continue
+		}
+
+		if !test.wantErr && got != test.want {
+			t.Errorf("BagStoreFor(%q) = %#v, want %#v", test.location, got, test.want)
+		}
+	}
+
+	got, err := BagStoreFor("s3://bucket/bag.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if store, ok := got.(S3BagStore); !ok || store.Object.Bucket != "bucket" || store.Object.Key != "bag.json" {
+		t.Errorf("BagStoreFor(s3://bucket/bag.json) = %#v, want an S3BagStore", got)
+	}
+}
+
+func TestSentenceID(t *testing.T) {
+	if SentenceID("He ran away.") != SentenceID("He ran away.") {
+		t.Errorf("SentenceID() differs for the same text")
+	}
+
+	if SentenceID("He ran away.") == SentenceID("He ran away!") {
+		t.Errorf("SentenceID() is the same for different text")
+	}
+}
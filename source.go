@@ -0,0 +1,466 @@
+package forensicfilescorpus
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CorpusSource is somewhere a corpus can be read from, such as a local file, an HTTP(S) URL or an
+// S3 compatible object store. Sources are usually created from a location with `SourceFor`.
+type CorpusSource interface {
+	// Open returns the contents of the corpus, as stored. It is not decompressed.
+	Open() (io.ReadCloser, error)
+
+	// Local returns the path of a local file holding the corpus, fetching it first if needed.
+	Local() (string, error)
+}
+
+// SourceCacheDir is where corpora fetched from remote sources are cached, along with the ETag
+// they were fetched with so they are only downloaded again when they change.
+var SourceCacheDir = filepath.Join(os.TempDir(), "ffcorpus")
+
+// SourceHTTPClient is the client used to fetch corpora from remote sources.
+var SourceHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// SourceFor returns the source for a location, chosen by the scheme of the location:
+//   - "file:///path/to/sentences.txt", or a path without a scheme, is a local file. A relative
+//     path can also be given, such as "file://sentences.txt".
+//   - "http://" and "https://" URLs are fetched with ETag caching, see `HTTPSource`.
+//   - "s3://bucket/key" is fetched from an S3 compatible object store, see `S3Source`.
+func SourceFor(location string) (CorpusSource, error) {
+	if !strings.Contains(location, "://") {
+		return FileSource(location), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return fileSourceFor(u)
+	case "http", "https":
+		return &HTTPSource{URL: location}, nil
+	case "s3":
+		return s3SourceFor(u), nil
+	}
+
+	return nil, fmt.Errorf("unsupported corpus location scheme %q", u.Scheme)
+}
+
+// fileSourceFor returns the source for a "file://" URL. The host is part of a relative path, so
+// "file://one.txt" is "one.txt", except for "localhost" which is the same as no host at all.
+func fileSourceFor(u *url.URL) (FileSource, error) {
+	location := u.Path
+	if u.Host != "" && u.Host != "localhost" {
+		location = u.Host + u.Path
+	}
+
+	if location == "" {
+		return "", errors.New("file corpus location has no path")
+	}
+
+	return FileSource(location), nil
+}
+
+// s3SourceFor returns the source for an "s3://bucket/key" URL.
+func s3SourceFor(u *url.URL) *S3Source {
+	return NewS3Source(u.Host, strings.TrimPrefix(u.Path, "/"))
+}
+
+// resolve returns a local path for the corpus at location, fetching it if it is remote. An empty
+// location is the default corpus and is returned as it is.
+func resolve(location string) (string, error) {
+	if location == "" || !strings.Contains(location, "://") {
+		return location, nil
+	}
+
+	source, err := SourceFor(location)
+	if err != nil {
+		return "", err
+	}
+
+	return source.Local()
+}
+
+// FileSource is a corpus stored in a local file.
+type FileSource string
+
+// Open opens the file.
+func (s FileSource) Open() (io.ReadCloser, error) {
+	return os.Open(string(s))
+}
+
+// Local returns the path of the file.
+func (s FileSource) Local() (string, error) {
+	return string(s), nil
+}
+
+// HTTPSource is a corpus fetched from an HTTP(S) URL. The corpus is cached within `SourceCacheDir`
+// and the cached copy is used for as long as the server responds to its ETag with 304 Not Modified.
+// If the server cannot be reached the cached copy is used as it is.
+type HTTPSource struct {
+	URL string
+}
+
+// Open fetches the corpus and opens the cached copy.
+func (s *HTTPSource) Open() (io.ReadCloser, error) {
+	return openLocal(s)
+}
+
+// Local fetches the corpus and returns the path of the cached copy.
+func (s *HTTPSource) Local() (string, error) {
+	return fetchCached(s.URL, func() (*http.Request, error) {
+		return http.NewRequest("GET", s.URL, nil)
+	})
+}
+
+// S3Source is a corpus stored in an S3 compatible object store, such as Amazon S3 or MinIO. Objects
+// are fetched using path style addressing and cached in the same way as `HTTPSource`.
+type S3Source struct {
+	Bucket string
+	Key    string
+
+	// Endpoint is the base URL of the object store, such as "http://localhost:9000" for a local
+	// MinIO server. Region, AccessKey, SecretKey and SessionToken are used to sign requests, and
+	// requests are sent unsigned when no AccessKey is given.
+	Endpoint     string
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// NewS3Source creates a source for an object, configured from the standard AWS environment
+// variables: AWS_REGION, AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN. The
+// endpoint is taken from AWS_ENDPOINT_URL when set, otherwise Amazon S3 for the region is used.
+func NewS3Source(bucket, key string) *S3Source {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := os.Getenv("AWS_ENDPOINT_URL")
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+
+	return &S3Source{
+		Bucket:       bucket,
+		Key:          key,
+		Endpoint:     endpoint,
+		Region:       region,
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// Open fetches the object and opens the cached copy.
+func (s *S3Source) Open() (io.ReadCloser, error) {
+	return openLocal(s)
+}
+
+// Local fetches the object and returns the path of the cached copy.
+func (s *S3Source) Local() (string, error) {
+	location := s.object()
+
+	return fetchCached(location, func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", location, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		s.sign(req, nil)
+		return req, nil
+	})
+}
+
+// object returns the URL of the object using path style addressing.
+func (s *S3Source) object() string {
+	return strings.TrimRight(s.Endpoint, "/") + "/" + s.Bucket + "/" + uriEncode(s.Key, false)
+}
+
+// get reads the object without caching it, returning `os.ErrNotExist` when there is no object.
+func (s *S3Source) get() ([]byte, error) {
+	req, err := http.NewRequest("GET", s.object(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign(req, nil)
+
+	res, err := SourceHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return ioutil.ReadAll(res.Body)
+	case http.StatusNotFound:
+		return nil, os.ErrNotExist
+	}
+
+	return nil, fmt.Errorf("unable to read %s: %s", s.object(), res.Status)
+}
+
+// put replaces the object with body.
+func (s *S3Source) put(body []byte) error {
+	req, err := http.NewRequest("PUT", s.object(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	s.sign(req, body)
+
+	res, err := SourceHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to write %s: %s", s.object(), res.Status)
+	}
+
+	return nil
+}
+
+// sign adds an AWS Signature Version 4 authorization header to req, which has the given body. A
+// request without a body is signed with an unsigned payload. Requests are left unsigned when the
+// source has no access key.
+func (s *S3Source) sign(req *http.Request, body []byte) {
+	s.signAt(req, body, time.Now())
+}
+
+// signAt signs req as `sign` does, as though it was sent at the given time.
+func (s *S3Source) signAt(req *http.Request, body []byte, at time.Time) {
+	if s.AccessKey == "" {
+		return
+	}
+
+	payload := "UNSIGNED-PAYLOAD"
+	if body != nil {
+		payload = hexSHA256(body)
+	}
+
+	now := at.UTC()
+	date := now.Format("20060102")
+	stamp := now.Format("20060102T150405Z")
+
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payload,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		stamp,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query as it is written in a SigV4 canonical request, with the
+// parameters sorted by name and then value, and every name and value encoded with `uriEncode`.
+// This differs from `url.Values.Encode`, which writes spaces as "+" rather than "%20".
+func canonicalQuery(query url.Values) string {
+	var params [][2]string
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, [2]string{uriEncode(name, true), uriEncode(value, true)})
+		}
+	}
+
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+
+		return params[i][1] < params[j][1]
+	})
+
+	encoded := make([]string, len(params))
+	for i, param := range params {
+		encoded[i] = param[0] + "=" + param[1]
+	}
+
+	return strings.Join(encoded, "&")
+}
+
+// uriEncode percent encodes every byte of s except the unreserved characters, as required by
+// SigV4. Slashes are kept unless encodeSlash is set, so that it can be used for object keys.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+			b.WriteByte(c)
+		case c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func openLocal(source CorpusSource) (io.ReadCloser, error) {
+	local, err := source.Local()
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(local)
+}
+
+// fetchCached downloads location into `SourceCacheDir`, returning the path of the cached copy.
+// The cached file keeps the extension of location so the format and compression of the corpus
+// can still be detected from it. The request is created by newRequest so that it can be signed.
+func fetchCached(location string, newRequest func() (*http.Request, error)) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+
+	name := hexSHA256([]byte(location))[:16] + "-" + path.Base(u.Path)
+	cached := filepath.Join(SourceCacheDir, name)
+	etagPath := cached + ".etag"
+
+	req, err := newRequest()
+	if err != nil {
+		return "", err
+	}
+
+	if etag, err := ioutil.ReadFile(etagPath); err == nil {
+		if _, err := os.Stat(cached); err == nil {
+			req.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	res, err := SourceHTTPClient.Do(req)
+	if err != nil {
+		if _, statErr := os.Stat(cached); statErr == nil {
+			return cached, nil
+		}
+
+		return "", err
+	}
+
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified:
+		return cached, nil
+	case res.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("unable to fetch corpus from %s: %s", location, res.Status)
+	}
+
+	if err := os.MkdirAll(SourceCacheDir, 0755); err != nil {
+		return "", err
+	}
+
+	tmp, err := ioutil.TempFile(SourceCacheDir, name+".*")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(tmp, res.Body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), cached); err != nil {
+		return "", err
+	}
+
+	if etag := res.Header.Get("ETag"); etag != "" {
+		ioutil.WriteFile(etagPath, []byte(etag), 0644)
+	} else {
+		os.Remove(etagPath)
+	}
+
+	return cached, nil
+}
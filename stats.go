@@ -0,0 +1,186 @@
+package forensicfilescorpus
+
+import "sort"
+
+// StatsPercentiles are the percentiles of sentence length reported by `Corpus.Stats`.
+var StatsPercentiles = []int{10, 25, 50, 75, 90, 95, 99}
+
+// StatsBuckets is the number of buckets within the length histogram reported by `Corpus.Stats`.
+var StatsBuckets = 10
+
+// Stats summarises the sentences within a corpus. Episodes and speakers are only counted when the
+// corpus carries that metadata, such as a corpus stored as JSON Lines.
+type Stats struct {
+	Sentences    int            `json:"sentences"`
+	Unit         string         `json:"unit"`
+	Length       LengthStats    `json:"length"`
+	Punctuation  map[string]int `json:"punctuation"`
+	Vocabulary   int            `json:"vocabulary"`
+	AverageWords float64        `json:"average_words"`
+	TopWords     []Count        `json:"top_words"`
+	TopBigrams   []Count        `json:"top_bigrams"`
+	Episodes     []Count        `json:"episodes,omitempty"`
+	Speakers     []Count        `json:"speakers,omitempty"`
+}
+
+// LengthStats is the distribution of sentence lengths within a corpus, measured in its unit.
+type LengthStats struct {
+	Min         int          `json:"min"`
+	Max         int          `json:"max"`
+	Mean        float64      `json:"mean"`
+	Percentiles []Percentile `json:"percentiles"`
+	Histogram   []Bucket     `json:"histogram"`
+}
+
+// Percentile is the length that P percent of sentences are no longer than.
+type Percentile struct {
+	P      int `json:"p"`
+	Length int `json:"length"`
+}
+
+// Bucket is the number of sentences with a length from Min to Max inclusive.
+type Bucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// Count is the number of times a word, bigram, episode or speaker appears within a corpus.
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// StatsFromFile is a convenience method to summarise the corpus within the file provided by path
+// parameter. Options are used to load the corpus, see `LoadCorpus`.
+func StatsFromFile(path string, top int, options ...Option) (Stats, error) {
+	corpus, err := LoadCorpus(path, options...)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	return corpus.Stats(top), nil
+}
+
+// Stats summarises the sentences within the corpus. Only the top most common words and bigrams are
+// reported, leaving out `Stopwords`. A bigram is two words next to each other within a sentence,
+// neither of which is a stopword. Every episode and speaker is reported, most common first.
+func (c *Corpus) Stats(top int) Stats {
+	stats := Stats{
+		Sentences:   len(c.sentences),
+		Unit:        c.unit.Name,
+		Length:      c.lengthStats(),
+		Punctuation: map[string]int{},
+	}
+
+	vocabulary := map[string]int{}
+	words := map[string]int{}
+	bigrams := map[string]int{}
+	episodes := map[string]int{}
+	speakers := map[string]int{}
+	totalWords := 0
+
+	for _, sentence := range c.sentences {
+		stats.Punctuation[sentence.End]++
+		totalWords += sentence.Words
+
+		previous := ""
+		for _, token := range Tokenise(sentence.Text) {
+			vocabulary[token.Text]++
+
+			if Stopwords[token.Text] {
+				previous = ""
+				continue
+			}
+
+			words[token.Text]++
+
+			if previous != "" {
+				bigrams[previous+" "+token.Text]++
+			}
+
+			previous = token.Text
+		}
+
+		if sentence.Episode != "" {
+			episodes[sentence.Episode]++
+		}
+
+		if sentence.Speaker != "" {
+			speakers[sentence.Speaker]++
+		}
+	}
+
+	stats.Vocabulary = len(vocabulary)
+	stats.TopWords = topCounts(words, top)
+	stats.TopBigrams = topCounts(bigrams, top)
+	stats.Episodes = topCounts(episodes, -1)
+	stats.Speakers = topCounts(speakers, -1)
+
+	if len(c.sentences) > 0 {
+		stats.AverageWords = float64(totalWords) / float64(len(c.sentences))
+	}
+
+	return stats
+}
+
+// lengthStats uses the lengths index, which is already in order, to find the distribution of
+// sentence lengths.
+func (c *Corpus) lengthStats() LengthStats {
+	var stats LengthStats
+
+	n := len(c.lengths)
+	if n == 0 {
+		return stats
+	}
+
+	stats.Min = c.lengths[0]
+	stats.Max = c.lengths[n-1]
+
+	total := 0
+	for _, length := range c.lengths {
+		total += length
+	}
+
+	stats.Mean = float64(total) / float64(n)
+
+	for _, p := range StatsPercentiles {
+		rank := (p*n + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+
+		stats.Percentiles = append(stats.Percentiles, Percentile{p, c.lengths[rank-1]})
+	}
+
+	width := (stats.Max-stats.Min)/StatsBuckets + 1
+	for min := stats.Min; min <= stats.Max; min += width {
+		lo, hi := c.lengthRange(min-1, min+width)
+		stats.Histogram = append(stats.Histogram, Bucket{min, min + width - 1, hi - lo})
+	}
+
+	return stats
+}
+
+// topCounts returns the top most common values within counts, most common first. Values with the
+// same count are ordered alphabetically. A negative top returns every value.
+func topCounts(counts map[string]int, top int) []Count {
+	var sorted []Count
+	for value, count := range counts {
+		sorted = append(sorted, Count{value, count})
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+
+		return sorted[i].Value < sorted[j].Value
+	})
+
+	if top >= 0 && len(sorted) > top {
+		sorted = sorted[:top]
+	}
+
+	return sorted
+}
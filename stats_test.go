@@ -0,0 +1,167 @@
+package forensicfilescorpus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCorpusStats(t *testing.T) {
+	stats := NewCorpus(testSentences).Stats(3)
+
+	if stats.Sentences != 5 || stats.Unit != "runes" {
+		t.Errorf("Stats() Sentences, Unit = %d, %q, want 5, %q", stats.Sentences, stats.Unit, "runes")
+	}
+
+	if want := map[string]int{".": 4, "?": 1}; !reflect.DeepEqual(stats.Punctuation, want) {
+		t.Errorf("Stats().Punctuation = %v, want %v", stats.Punctuation, want)
+	}
+
+	if stats.Vocabulary != 19 {
+		t.Errorf("Stats().Vocabulary = %d, want 19", stats.Vocabulary)
+	}
+
+	if stats.AverageWords != 4.6 {
+		t.Errorf("Stats().AverageWords = %v, want 4.6", stats.AverageWords)
+	}
+
+	if want := []Count{{"found", 2}, {"away", 1}, {"bank", 1}}; !reflect.DeepEqual(stats.TopWords, want) {
+		t.Errorf("Stats().TopWords = %v, want %v", stats.TopWords, want)
+	}
+
+	want := []Count{{"police searched", 1}, {"ran away", 1}, {"river bank", 1}}
+	if !reflect.DeepEqual(stats.TopBigrams, want) {
+		t.Errorf("Stats().TopBigrams = %v, want %v", stats.TopBigrams, want)
+	}
+
+	if stats.Episodes != nil || stats.Speakers != nil {
+		t.Errorf("Stats() Episodes, Speakers = %v, %v, want none without metadata", stats.Episodes, stats.Speakers)
+	}
+}
+
+func TestCorpusStatsLength(t *testing.T) {
+	// testSentences are 12, 16, 18, 31 and 39 runes long.
+	length := NewCorpus(testSentences).Stats(0).Length
+
+	if length.Min != 12 || length.Max != 39 || length.Mean != 23.2 {
+		t.Errorf("Stats().Length Min, Max, Mean = %d, %d, %v, want 12, 39, 23.2", length.Min, length.Max, length.Mean)
+	}
+
+	percentiles := []Percentile{{10, 12}, {25, 16}, {50, 18}, {75, 31}, {90, 39}, {95, 39}, {99, 39}}
+	if !reflect.DeepEqual(length.Percentiles, percentiles) {
+		t.Errorf("Stats().Length.Percentiles = %v, want %v", length.Percentiles, percentiles)
+	}
+
+	histogram := []Bucket{
+		{12, 14, 1}, {15, 17, 1}, {18, 20, 1}, {21, 23, 0}, {24, 26, 0},
+		{27, 29, 0}, {30, 32, 1}, {33, 35, 0}, {36, 38, 0}, {39, 41, 1},
+	}
+	if !reflect.DeepEqual(length.Histogram, histogram) {
+		t.Errorf("Stats().Length.Histogram = %v, want %v", length.Histogram, histogram)
+	}
+}
+
+func TestCorpusStatsEdgeCases(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentences []string
+		want      LengthStats
+	}{
+		{"empty", nil, LengthStats{}},
+		{
+			name:      "single length",
+			sentences: []string{"He ran away.", "He ran away."},
+			want: LengthStats{
+				Min:  12,
+				Max:  12,
+				Mean: 12,
+				Percentiles: []Percentile{
+					{10, 12}, {25, 12}, {50, 12}, {75, 12}, {90, 12}, {95, 12}, {99, 12},
+				},
+				Histogram: []Bucket{{12, 12, 2}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		stats := NewCorpus(test.sentences).Stats(10)
+
+		if !reflect.DeepEqual(stats.Length, test.want) {
+			t.Errorf("%s: Stats().Length = %+v, want %+v", test.name, stats.Length, test.want)
+		}
+
+		if test.sentences == nil && stats.AverageWords != 0 {
+			t.Errorf("%s: Stats().AverageWords = %v, want 0", test.name, stats.AverageWords)
+		}
+	}
+}
+
+func TestCorpusStatsMetadata(t *testing.T) {
+	corpus := NewCorpusFromSentences([]Sentence{
+		{Text: "He ran away.", Episode: "s01e01", Speaker: "Skip Palenik"},
+		{Text: "The van was found.", Episode: "s01e01"},
+		{Text: "Is this the man?", Episode: "s01e02", Speaker: "Skip Palenik"},
+		{Text: "Police searched the river bank.", Episode: "s01e02", Speaker: "Narrator"},
+		{Text: "Fibers were found on the victim's coat.", Episode: "s01e02"},
+	})
+
+	stats := corpus.Stats(0)
+
+	if want := []Count{{"s01e02", 3}, {"s01e01", 2}}; !reflect.DeepEqual(stats.Episodes, want) {
+		t.Errorf("Stats().Episodes = %v, want %v", stats.Episodes, want)
+	}
+
+	if want := []Count{{"Skip Palenik", 2}, {"Narrator", 1}}; !reflect.DeepEqual(stats.Speakers, want) {
+		t.Errorf("Stats().Speakers = %v, want %v", stats.Speakers, want)
+	}
+
+	if len(stats.TopWords) != 0 || len(stats.TopBigrams) != 0 {
+		t.Errorf("Stats(0) TopWords, TopBigrams = %v, %v, want none", stats.TopWords, stats.TopBigrams)
+	}
+}
+
+func TestTopCounts(t *testing.T) {
+	counts := map[string]int{"fibers": 3, "carpet": 3, "van": 5, "dna": 1}
+
+	tests := []struct {
+		top  int
+		want []Count
+	}{
+		{0, nil},
+		{1, []Count{{"van", 5}}},
+		{3, []Count{{"van", 5}, {"carpet", 3}, {"fibers", 3}}},
+		{-1, []Count{{"van", 5}, {"carpet", 3}, {"fibers", 3}, {"dna", 1}}},
+		{10, []Count{{"van", 5}, {"carpet", 3}, {"fibers", 3}, {"dna", 1}}},
+	}
+
+	for _, test := range tests {
+		got := topCounts(counts, test.top)
+		if len(got) == 0 && len(test.want) == 0 {
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("topCounts(%d) = %v, want %v", test.top, got, test.want)
+		}
+	}
+}
+
+func TestTokenise(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Token
+	}{
+		{"", nil},
+		{"He ran.", []Token{{"he", 0, 2}, {"ran", 3, 6}}},
+		{"Don't RUN!", []Token{{"don't", 0, 5}, {"run", 6, 9}}},
+		{"Helle\u2019s van", []Token{{"helle's", 0, 9}, {"van", 10, 13}}},
+		{"the 'quoted' word", []Token{{"the", 0, 3}, {"quoted", 5, 11}, {"word", 13, 17}}},
+		{"dogs' fur", []Token{{"dogs", 0, 4}, {"fur", 6, 9}}},
+		{"DNA-test 42", []Token{{"dna", 0, 3}, {"test", 4, 8}, {"42", 9, 11}}},
+	}
+
+	for _, test := range tests {
+		if got := Tokenise(test.input); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Tokenise(%q) = %v, want %v", test.input, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package forensicfilescorpus
+
+import (
+	"errors"
+	"io"
+)
+
+// SampleReader draws up to k sentences from r, where each sentence is on its own line as either
+// plain text or JSON Lines, in a single pass using reservoir sampling. Only the k chosen sentences
+// are held in memory, so this can be used on corpora too large to load. Every sentence accepted by
+// filter is equally likely to be chosen, and no line is chosen more than once. If fewer than k
+// sentences are accepted then all of them are returned. Options are used for the unit to measure
+// sentences and the source of randomisation, as with `NewCorpus`.
+func SampleReader(r io.Reader, k int, filter Filter, options ...Option) ([]string, error) {
+	if k < 1 {
+		return nil, errors.New("must sample at least one sentence")
+	}
+
+	return sample(newSentenceScanner(r, formatUnknown), k, filter, options)
+}
+
+func sample(scanner *sentenceScanner, k int, filter Filter, options []Option) ([]string, error) {
+	c := configure(options)
+	reservoir := make([]string, 0, k)
+	seen, inRange := 0, 0
+
+	for scanner.Scan() {
+		sentence := c.measure(scanner.Sentence())
+
+		if filter != nil && !filter(sentence) {
+			continue
+		}
+
+		inRange++
+		if c.filter != nil && !c.filter(sentence) {
+			continue
+		}
+
+		seen++
+
+		if len(reservoir) < k {
+			reservoir = append(reservoir, sentence.Text)
+			continue
+		}
+
+		if j := c.intn(seen); j < k {
+			reservoir[j] = sentence.Text
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sentences accepted by filter but not by the corpus filter are reported as with `Pick`.
+	if len(reservoir) == 0 && inRange > 0 {
+		return nil, errNoMatch
+	}
+
+	if len(reservoir) == 0 {
+		return nil, errNoCandidates
+	}
+
+	// The reservoir holds sentences in the order they were first seen until it is full, so shuffle
+	// it to avoid favouring sentences from the start of the file.
+	for i := len(reservoir) - 1; i > 0; i-- {
+		j := c.intn(i + 1)
+		reservoir[i], reservoir[j] = reservoir[j], reservoir[i]
+	}
+
+	return reservoir, nil
+}
+
+// SampleFile draws up to k sentences from the file provided by path parameter. See `SampleReader`.
+func SampleFile(path string, k int, filter Filter, options ...Option) ([]string, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer src.Close()
+
+	if k < 1 {
+		return nil, errors.New("must sample at least one sentence")
+	}
+
+	return sample(newSentenceScanner(src, formatOf(path)), k, filter, options)
+}
+
+// StreamPickFromFile picks a random sentence from the file provided by path parameter without
+// loading the file into memory. The min and max values are the same as for `Pick`. Compiled
+// corpus files are already read without loading them, and are handed to `PickFromFile`, as are
+// weighted picks using `WithScorer` which need every sentence to be scored.
+func StreamPickFromFile(path string, min, max int, options ...Option) (string, error) {
+	path, err := resolve(path)
+	if err != nil {
+		return "", err
+	}
+
+	if IsCompiled(path) || configure(options).scorer != nil {
+		return PickFromFile(path, min, max, options...)
+	}
+
+	if _, _, err := pickRange(min, max, configure(options).unit); err != nil {
+		return "", err
+	}
+
+	sentences, err := SampleFile(path, 1, Between(min, max), options...)
+	if err != nil {
+		return "", err
+	}
+
+	return sentences[0], nil
+}
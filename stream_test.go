@@ -0,0 +1,125 @@
+package forensicfilescorpus
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSentences writes each sentence on its own line to a file within dir, returning the path of
+// the file.
+func writeSentences(t *testing.T, dir, name string, sentences []string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(strings.Join(sentences, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestStreamPickValidatesLikePick(t *testing.T) {
+	path := writeSentences(t, t.TempDir(), "sentences.txt", testSentences)
+	corpus := NewCorpus(testSentences)
+
+	tests := []struct {
+		min, max int
+	}{
+		{-1, -1},
+		{12, 31},
+		{20, 10},
+		{-1, MinimumLineLength - 1},
+		{-1, 0},
+		{39, 100},
+	}
+
+	for _, test := range tests {
+		_, want := corpus.Pick(test.min, test.max)
+		_, got := StreamPickFromFile(path, test.min, test.max)
+
+		if got != want && (got == nil || want == nil || got.Error() != want.Error()) {
+			t.Errorf("StreamPickFromFile(%d, %d) error = %v, want %v", test.min, test.max, got, want)
+		}
+	}
+
+	// Sentences within the length range that the corpus filter rejects are reported as with Pick.
+	filtered := []Option{WithFilter(EndsWith("!"))}
+	if _, err := StreamPickFromFile(path, -1, -1, filtered...); err != errNoMatch {
+		t.Errorf("StreamPickFromFile() with a filter error = %v, want %v", err, errNoMatch)
+	}
+
+	if _, err := StreamPickFromFile(path, 100, -1, filtered...); err != errNoCandidates {
+		t.Errorf("StreamPickFromFile(100, -1) with a filter error = %v, want %v", err, errNoCandidates)
+	}
+}
+
+func TestSampleReader(t *testing.T) {
+	tests := []struct {
+		name   string
+		k      int
+		filter Filter
+		want   int
+		err    bool
+	}{
+		{"one", 1, nil, 1, false},
+		{"some", 3, nil, 3, false},
+		{"more than available", 10, nil, len(testSentences), false},
+		{"filtered", 10, Between(20, -1), 2, false},
+		{"nothing accepted", 1, Between(100, -1), 0, true},
+		{"none", 0, nil, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := SampleReader(strings.NewReader(strings.Join(testSentences, "\n")), test.k, test.filter)
+
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: SampleReader() = %q, want an error", test.name, got)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: SampleReader() error = %v", test.name, err)
+		}
+
+		if len(got) != test.want {
+			t.Errorf("%s: SampleReader() returned %d sentences, want %d", test.name, len(got), test.want)
+		}
+
+		seen := map[string]bool{}
+		for _, sentence := range got {
+			if seen[sentence] || !containsString(testSentences, sentence) {
+				t.Errorf("%s: SampleReader() = %q, want distinct sentences from the corpus", test.name, got)
+			}
+
+			seen[sentence] = true
+		}
+	}
+}
+
+func TestSampleReaderIsUniform(t *testing.T) {
+	counts := map[string]int{}
+	text := strings.Join(testSentences, "\n")
+
+	for i := 0; i < 5000; i++ {
+		got, err := SampleReader(strings.NewReader(text), 2, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, sentence := range got {
+			counts[sentence]++
+		}
+	}
+
+	// Each sentence is expected in 2000 of the samples.
+	for _, sentence := range testSentences {
+		if counts[sentence] < 1800 || counts[sentence] > 2200 {
+			t.Errorf("sentence %q sampled %d times, want about 2000", sentence, counts[sentence])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package forensicfilescorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timecode is a position within an episode, such as when a subtitle is shown. It is written in the
+// same way as an SRT timecode, "00:01:02,345".
+type Timecode time.Duration
+
+// String formats the timecode as an SRT timecode.
+func (t Timecode) String() string {
+	d := time.Duration(t)
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+	millis := (d % time.Second) / time.Millisecond
+
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, millis)
+}
+
+// MarshalJSON writes the timecode as an SRT timecode string.
+func (t Timecode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON reads a timecode written as an SRT timecode string.
+func (t *Timecode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	d, err := parseTimecode(s)
+	if err != nil {
+		return err
+	}
+
+	*t = Timecode(d)
+	return nil
+}
+
+// parseTimecode parses an SRT timecode such as "00:01:02,345" into a duration.
+func parseTimecode(tc string) (time.Duration, error) {
+	parts := strings.Split(strings.Replace(strings.TrimSpace(tc), ",", ".", 1), ":")
+
+	if len(parts) != 3 {
+		return 0, errors.New("invalid timecode")
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, err
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, err
+	}
+
+	// The seconds and their fraction are parsed as integers, as a float cannot hold every number of
+	// milliseconds exactly and would not round trip through `Timecode.String`.
+	whole, fraction := parts[2], ""
+	if dot := strings.Index(whole, "."); dot >= 0 {
+		whole, fraction = whole[:dot], whole[dot+1:]
+	}
+
+	seconds, err := strconv.Atoi(whole)
+	if err != nil {
+		return 0, err
+	}
+
+	var nanos int
+	if fraction != "" {
+		if len(fraction) > 9 || strings.Trim(fraction, "0123456789") != "" {
+			return 0, errors.New("invalid timecode")
+		}
+
+		nanos, err = strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	d += time.Duration(seconds)*time.Second + time.Duration(nanos)
+
+	return d, nil
+}
@@ -0,0 +1,86 @@
+package forensicfilescorpus
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a single word within a sentence, lowercased, along with the byte offsets of the word
+// within the sentence so that it can be highlighted.
+type Token struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// Stopwords are common words that are left out of word and bigram counts, see `Corpus.Stats`.
+var Stopwords = stringSet(`a about after all also am an and any are as at be because been before
+being but by can could did do does doing don't down for from had has have he her here him his how
+i i'm if in into is it it's its just know me my no not now of off on one or our out over said she
+so some than that that's the their them then there they this those through to too up us very was
+we were what when where which who why will with would yeah yes you you're your`)
+
+// Tokenise splits s into lowercased words. Words are runs of letters and digits, and may contain
+// apostrophes between letters, so "don't" and "Helle's" are single words.
+func Tokenise(s string) []Token {
+	var tokens []Token
+	start := -1
+
+	for i, r := range s {
+		if isWordRune(r) || start >= 0 && isApostrophe(r) && isWordRune(nextRune(s, i)) {
+			if start < 0 {
+				start = i
+			}
+
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, newToken(s, start, i))
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, newToken(s, start, len(s)))
+	}
+
+	return tokens
+}
+
+func newToken(s string, start, end int) Token {
+	text := strings.ToLower(s[start:end])
+	text = strings.Replace(text, "’", "'", -1)
+	return Token{text, start, end}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// nextRune returns the rune following the one at byte offset i within s, or -1 at the end of s.
+func nextRune(s string, i int) rune {
+	_, size := utf8.DecodeRuneInString(s[i:])
+
+	if i+size >= len(s) {
+		return -1
+	}
+
+	r, _ := utf8.DecodeRuneInString(s[i+size:])
+	return r
+}
+
+func stringSet(words string) map[string]bool {
+	set := map[string]bool{}
+
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+
+	return set
+}